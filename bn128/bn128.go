@@ -23,6 +23,7 @@ type Bn128 struct {
 	G2            G2
 	LoopCount     *big.Int
 	LoopCountNeg  bool
+	U             *big.Int // BN parameter, with LoopCount = 6U+2
 
 	TwoInv             *big.Int
 	CoefB              *big.Int
@@ -97,13 +98,20 @@ func NewBn128() (Bn128, error) {
 	b.Fq12 = fields.NewFq12(b.Fq6, b.Fq2, b.NonResidueFq6)
 
 	b.G1 = NewG1(b.Fq1, b.Gg1)
+	b.G1.R = b.R
 	b.G2 = NewG2(b.Fq2, b.Gg2)
+	b.G2.R = b.R
 
 	err := b.preparePairing()
 	if err != nil {
 		return b, err
 	}
 
+	err = b.prepareGLV()
+	if err != nil {
+		return b, err
+	}
+
 	return b, nil
 }
 
@@ -117,6 +125,24 @@ func NewFqR() (fields.Fq, error) {
 	return fqR, nil
 }
 
+// NewFqMontQ returns a new Montgomery form Finite Field over Q, the base field
+func NewFqMontQ() (fields.FqMont, error) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	if !ok {
+		return fields.FqMont{}, errors.New("err parsing Q")
+	}
+	return fields.NewFqMont(q)
+}
+
+// NewFqMontR returns a new Montgomery form Finite Field over R, the scalar field
+func NewFqMontR() (fields.FqMont, error) {
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	if !ok {
+		return fields.FqMont{}, errors.New("err parsing R")
+	}
+	return fields.NewFqMont(r)
+}
+
 func (bn128 *Bn128) preparePairing() error {
 	var ok bool
 	bn128.LoopCount, ok = new(big.Int).SetString("29793968203157093288", 10)
@@ -126,6 +152,11 @@ func (bn128 *Bn128) preparePairing() error {
 
 	bn128.LoopCountNeg = false
 
+	bn128.U, ok = new(big.Int).SetString("4965661367192848881", 10)
+	if !ok {
+		return errors.New("err with U from string")
+	}
+
 	bn128.TwoInv = bn128.Fq1.Inverse(big.NewInt(int64(2)))
 
 	bn128.CoefB = big.NewInt(int64(3))
@@ -175,16 +206,56 @@ func (bn128 *Bn128) preparePairing() error {
 
 }
 
+// prepareGLV sets the endomorphisms of G1 and G2 and the parameters of the
+// scalar decomposition for MulScalarGLV and MulScalarGLS
+func (bn128 *Bn128) prepareGLV() error {
+	beta, ok := new(big.Int).SetString("2203960485148121921418603742825762020974279258880205651966", 10)
+	if !ok {
+		return errors.New("error parsing beta")
+	}
+	lambda, ok := new(big.Int).SetString("4407920970296243842393367215006156084916469457145843978461", 10)
+	if !ok {
+		return errors.New("error parsing lambda")
+	}
+	bn128.G1.beta = beta
+	bn128.G1.glv = newGLV(lambda, bn128.R)
+
+	bn128.G2.psiX = bn128.TwistMulByQX
+	bn128.G2.psiY = bn128.TwistMulByQY
+	bn128.G2.glv = newGLV(new(big.Int).Mod(bn128.Q, bn128.R), bn128.R)
+	return nil
+}
+
 // Pairing calculates the BN128 Pairing of two given values
 func (bn128 Bn128) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
+	pre2 := bn128.PrecomputeG2(p2)
+	return bn128.PairingPrecomputed(p1, &pre2)
+}
+
+// PairingPrecomputed calculates the BN128 Pairing of p1 and the G2 point of
+// the precomputation, to pair many times with a fixed G2 point
+func (bn128 Bn128) PairingPrecomputed(p1 [3]*big.Int, pre2 *AteG2Precomp) [2][3][2]*big.Int {
+	if bn128.G1.IsZero(p1) || pre2.infinity {
+		return bn128.Fq12.One()
+	}
 	pre1 := bn128.preComputeG1(p1)
-	pre2 := bn128.preComputeG2(p2)
 
-	r1 := bn128.MillerLoop(pre1, pre2)
+	r1 := bn128.MillerLoop(pre1, *pre2)
 	res := bn128.finalExponentiation(r1)
 	return res
 }
 
+// PairingCheckPrecomputed returns if the product of the pairings of the G1
+// points and the G2 points of the precomputations is one, with a
+// MultiMillerLoop and only one final exponentiation
+func (bn128 Bn128) PairingCheckPrecomputed(ps1 [][3]*big.Int, pres2 []*AteG2Precomp) (bool, error) {
+	f, err := bn128.MultiMillerLoop(ps1, pres2)
+	if err != nil {
+		return false, err
+	}
+	return bn128.Fq12.Equal(bn128.finalExponentiation(f), bn128.Fq12.One()), nil
+}
+
 type AteG1Precomp struct {
 	Px *big.Int
 	Py *big.Int
@@ -204,18 +275,28 @@ type EllCoeffs struct {
 	EllVW [2]*big.Int
 	EllVV [2]*big.Int
 }
+
+// AteG2Precomp are the line coefficients of the Miller loop for a G2 point,
+// which only depend on the G2 point
 type AteG2Precomp struct {
 	Qx     [2]*big.Int
 	Qy     [2]*big.Int
 	Coeffs []EllCoeffs
+
+	infinity bool // the G2 point is the infinity point, and it has no Coeffs
 }
 
-func (bn128 Bn128) preComputeG2(p [3][2]*big.Int) AteG2Precomp {
+// PrecomputeG2 computes the line coefficients of the G2 point, which can be
+// reused for all the pairings with it (PairingPrecomputed, MultiMillerLoop)
+func (bn128 Bn128) PrecomputeG2(p [3][2]*big.Int) AteG2Precomp {
+	if bn128.G2.IsZero(p) {
+		return AteG2Precomp{Qx: bn128.Fq2.Zero(), Qy: bn128.Fq2.Zero(), infinity: true}
+	}
 	qCopy := bn128.G2.Affine(p)
 	res := AteG2Precomp{
-		qCopy[0],
-		qCopy[1],
-		[]EllCoeffs{},
+		Qx:     qCopy[0],
+		Qy:     qCopy[1],
+		Coeffs: []EllCoeffs{},
 	}
 	r := [3][2]*big.Int{
 		bn128.Fq2.Copy(qCopy[0]),
@@ -399,6 +480,56 @@ func (bn128 Bn128) MillerLoop(pre1 AteG1Precomp, pre2 AteG2Precomp) [2][3][2]*bi
 	return f
 }
 
+// MultiMillerLoop returns the product of the Miller loops of the G1 points
+// and the G2 points of the precomputations, sharing the squarings of the
+// accumulator. The pairs with an infinity point are skipped, and it returns
+// an error if there is not one precomputation for each G1 point
+func (bn128 Bn128) MultiMillerLoop(ps1 [][3]*big.Int, pres2 []*AteG2Precomp) ([2][3][2]*big.Int, error) {
+	if len(ps1) != len(pres2) {
+		return [2][3][2]*big.Int{}, errors.New("different number of G1 points and G2 precomputations")
+	}
+	var pres1 []AteG1Precomp
+	var coeffs [][]EllCoeffs
+	for i := range ps1 {
+		if pres2[i] == nil {
+			return [2][3][2]*big.Int{}, errors.New("nil G2 precomputation")
+		}
+		if bn128.G1.IsZero(ps1[i]) || pres2[i].infinity {
+			continue
+		}
+		pres1 = append(pres1, bn128.preComputeG1(ps1[i]))
+		coeffs = append(coeffs, pres2[i].Coeffs)
+	}
+
+	idx := 0
+	f := bn128.Fq12.One()
+	// step multiplies f by the lines of the coefficients idx of all the pairs
+	step := func() {
+		for j, pre1 := range pres1 {
+			c := coeffs[j][idx]
+			f = bn128.mulBy024(f,
+				c.Ell0,
+				bn128.Fq2.MulScalar(c.EllVW, pre1.Py),
+				bn128.Fq2.MulScalar(c.EllVV, pre1.Px))
+		}
+		idx++
+	}
+
+	for i := bn128.LoopCount.BitLen() - 2; i >= 0; i-- {
+		f = bn128.Fq12.Square(f)
+		step()
+		if bn128.LoopCount.Bit(i) == 1 {
+			step()
+		}
+	}
+	if bn128.LoopCountNeg {
+		f = bn128.Fq12.Inverse(f)
+	}
+	step()
+	step()
+	return f, nil
+}
+
 func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int) [2][3][2]*big.Int {
 	b := [2][3][2]*big.Int{
 		[3][2]*big.Int{
@@ -415,7 +546,53 @@ func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int)
 	return bn128.Fq12.Mul(a, b)
 }
 
+// finalExponentiation raises r to FinalExp = (q^12-1)/r, as the easy part
+// (q^6-1)(q^2+1) with Frobenius maps and the hard part (q^4-q^2+1)/r with
+// exponentiations by U in the cyclotomic subgroup
 func (bn128 Bn128) finalExponentiation(r [2][3][2]*big.Int) [2][3][2]*big.Int {
-	res := bn128.Fq12.Exp(r, bn128.FinalExp)
-	return res
+	f := bn128.finalExponentiationEasyPart(r)
+	return bn128.finalExponentiationHardPart(f)
+}
+
+// finalExponentiationEasyPart returns r^((q^6-1)(q^2+1)), which is in the
+// cyclotomic subgroup
+func (bn128 Bn128) finalExponentiationEasyPart(r [2][3][2]*big.Int) [2][3][2]*big.Int {
+	f := bn128.Fq12.Mul(bn128.Fq12.Conjugate(r), bn128.Fq12.Inverse(r))
+	return bn128.Fq12.Mul(bn128.Fq12.Frobenius(f, 2), f)
+}
+
+// finalExponentiationHardPart returns f^((q^4-q^2+1)/r), with the addition
+// chain of Devegili, Scott and Dahab (https://eprint.iacr.org/2007/390.pdf,
+// section 5), where the inverses are conjugates
+func (bn128 Bn128) finalExponentiationHardPart(f [2][3][2]*big.Int) [2][3][2]*big.Int {
+	fq12 := bn128.Fq12
+
+	fp := fq12.Frobenius(f, 1)
+	fp2 := fq12.Frobenius(f, 2)
+	fp3 := fq12.Frobenius(fp2, 1)
+
+	fu := fq12.CyclotomicExp(f, bn128.U)
+	fu2 := fq12.CyclotomicExp(fu, bn128.U)
+	fu3 := fq12.CyclotomicExp(fu2, bn128.U)
+
+	fup := fq12.Frobenius(fu, 1)
+	fu2p := fq12.Frobenius(fu2, 1)
+	fu3p := fq12.Frobenius(fu3, 1)
+	fu2p2 := fq12.Frobenius(fu2, 2)
+
+	y0 := fq12.Mul(fq12.Mul(fp, fp2), fp3)
+	y1 := fq12.Conjugate(f)
+	y2 := fu2p2
+	y3 := fq12.Conjugate(fup)
+	y4 := fq12.Conjugate(fq12.Mul(fu, fu2p))
+	y5 := fq12.Conjugate(fu2)
+	y6 := fq12.Conjugate(fq12.Mul(fu3, fu3p))
+
+	t0 := fq12.Mul(fq12.Mul(fq12.CyclotomicSquare(y6), y4), y5)
+	t1 := fq12.Mul(fq12.Mul(y3, y5), t0)
+	t0 = fq12.Mul(t0, y2)
+	t1 = fq12.CyclotomicSquare(fq12.Mul(fq12.CyclotomicSquare(t1), t0))
+	t0 = fq12.Mul(t1, y1)
+	t1 = fq12.Mul(t1, y0)
+	return fq12.Mul(fq12.CyclotomicSquare(t0), t1)
 }
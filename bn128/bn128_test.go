@@ -22,10 +22,10 @@ func TestBN128(t *testing.T) {
 	g2b := bn128.G2.MulScalar(bn128.G2.G, bn128.Fq1.Copy(big40))
 
 	pre1a := bn128.preComputeG1(g1a)
-	pre2a := bn128.preComputeG2(g2a)
+	pre2a := bn128.PrecomputeG2(g2a)
 	assert.Nil(t, err)
 	pre1b := bn128.preComputeG1(g1b)
-	pre2b := bn128.preComputeG2(g2b)
+	pre2b := bn128.PrecomputeG2(g2b)
 	assert.Nil(t, err)
 
 	r1 := bn128.MillerLoop(pre1a, pre2a)
@@ -86,3 +86,88 @@ func TestBN128Pairing2(t *testing.T) {
 	assert.True(t, bn.Fq12.Equal(gt6, bn.Pairing(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(2))), bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))))))
 
 }
+
+func TestBN128FqMont(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	fqR, err := NewFqR()
+	assert.Nil(t, err)
+
+	fmQ, err := NewFqMontQ()
+	assert.Nil(t, err)
+	fmR, err := NewFqMontR()
+	assert.Nil(t, err)
+	assert.Equal(t, bn128.Q, fmQ.Q)
+	assert.Equal(t, bn128.R, fmR.Q)
+
+	// y^2 = x^3 + 3 for the G1 generator
+	x := fmQ.SetBig(bn128.G1.G[0])
+	y := fmQ.SetBig(bn128.G1.G[1])
+	assert.True(t, fmQ.Equal(fmQ.Square(y), fmQ.Add(fmQ.Mul(fmQ.Square(x), x), fmQ.SetBig(bn128.CoefB))))
+
+	a, err := fqR.Rand()
+	assert.Nil(t, err)
+	assert.Equal(t, fqR.Inverse(a).String(), fmR.Big(fmR.Inverse(fmR.SetBig(a))).String())
+}
+
+func TestBN128FinalExponentiation(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1 := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(25)))
+	g2 := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(30)))
+	f := bn128.MillerLoop(bn128.preComputeG1(g1), bn128.PrecomputeG2(g2))
+
+	// same result than the exponentiation to FinalExp
+	res := bn128.finalExponentiation(f)
+	expected := bn128.Fq12.Exp(f, bn128.FinalExp)
+	assert.True(t, bn128.Fq12.Equal(expected, res))
+	assert.False(t, bn128.Fq12.Equal(bn128.Fq12.One(), res))
+
+	// the result is in the subgroup of order r
+	assert.True(t, bn128.Fq12.Equal(bn128.Fq12.One(), bn128.Fq12.CyclotomicExp(res, bn128.R)))
+	assert.Equal(t, new(big.Int).Add(new(big.Int).Mul(big.NewInt(int64(6)), bn128.U), big.NewInt(int64(2))), bn128.LoopCount)
+}
+
+func TestBN128PairingPrecomputed(t *testing.T) {
+	bn, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1a := bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(3)))
+	g2a := bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(2)))
+	pre2a := bn.PrecomputeG2(g2a)
+	assert.True(t, bn.Fq12.Equal(bn.Pairing(g1a, g2a), bn.PairingPrecomputed(g1a, &pre2a)))
+
+	// e(3*g1, 2*g2) * e(-6*g1, g2) == 1
+	g1b := bn.G1.Neg(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(6))))
+	pre2g := bn.PrecomputeG2(bn.G2.G)
+	ok, err := bn.PairingCheckPrecomputed([][3]*big.Int{g1a, g1b}, []*AteG2Precomp{&pre2a, &pre2g})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = bn.PairingCheckPrecomputed([][3]*big.Int{g1a, g1a}, []*AteG2Precomp{&pre2a, &pre2g})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// the multi Miller loop is the product of the Miller loops
+	f, err := bn.MultiMillerLoop([][3]*big.Int{g1a, g1b}, []*AteG2Precomp{&pre2a, &pre2g})
+	assert.Nil(t, err)
+	assert.True(t, bn.Fq12.Equal(
+		bn.Fq12.Mul(bn.MillerLoop(bn.preComputeG1(g1a), pre2a), bn.MillerLoop(bn.preComputeG1(g1b), pre2g)), f))
+
+	// the numbers of points must match
+	_, err = bn.MultiMillerLoop([][3]*big.Int{g1a, g1b}, []*AteG2Precomp{&pre2a})
+	assert.NotNil(t, err)
+	_, err = bn.PairingCheckPrecomputed([][3]*big.Int{g1a}, []*AteG2Precomp{&pre2a, &pre2g})
+	assert.NotNil(t, err)
+	_, err = bn.MultiMillerLoop([][3]*big.Int{g1a}, []*AteG2Precomp{nil})
+	assert.NotNil(t, err)
+
+	// the pairs with an infinity point are skipped
+	pre2z := bn.PrecomputeG2(bn.G2.Zero())
+	zero := [3]*big.Int{bn.Fq1.Zero(), bn.Fq1.One(), bn.Fq1.Zero()}
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.One(), bn.PairingPrecomputed(g1a, &pre2z)))
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.One(), bn.Pairing(zero, g2a)))
+	ok, err = bn.PairingCheckPrecomputed([][3]*big.Int{g1a, g1b, g1a, zero}, []*AteG2Precomp{&pre2a, &pre2g, &pre2z, &pre2a})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
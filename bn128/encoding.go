@@ -0,0 +1,284 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The byte encoding of the points is the one of the Ethereum precompiled
+// contracts (EIP-196 and EIP-197): the affine coordinates as 32 bytes big
+// endian integers, with the Fq2 elements x0 + x1*u encoded as x1, x0
+// (imaginary first), and the infinity point encoded as all zeros
+
+const (
+	// G1Size is the size of the byte encoding of a G1 point
+	G1Size = 64
+	// G2Size is the size of the byte encoding of a G2 point
+	G2Size = 128
+)
+
+// putInt writes the integer as 32 bytes big endian into b
+func putInt(b []byte, n *big.Int) {
+	nb := n.Bytes()
+	copy(b[32-len(nb):32], nb)
+}
+
+// fqFromBytes reads a 32 bytes big endian integer, which must be lower than q
+func (bn128 Bn128) fqFromBytes(b []byte) (*big.Int, error) {
+	n := new(big.Int).SetBytes(b[:32])
+	if n.Cmp(bn128.Q) != -1 {
+		return nil, errors.New("coordinate not lower than the field modulus")
+	}
+	return n, nil
+}
+
+// G1ToBytes returns the 64 bytes encoding of the G1 point
+func (bn128 Bn128) G1ToBytes(p [3]*big.Int) []byte {
+	b := make([]byte, G1Size)
+	if bn128.G1.IsZero(p) {
+		return b
+	}
+	a := bn128.G1.Affine(p)
+	putInt(b[0:], bn128.Fq1.Affine(a[0]))
+	putInt(b[32:], bn128.Fq1.Affine(a[1]))
+	return b
+}
+
+// G1FromBytes decodes the 64 bytes encoding of a G1 point, checking that it is
+// on the curve
+func (bn128 Bn128) G1FromBytes(b []byte) ([3]*big.Int, error) {
+	if len(b) != G1Size {
+		return [3]*big.Int{}, errors.New("invalid G1 point length")
+	}
+	x, err := bn128.fqFromBytes(b[0:])
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	y, err := bn128.fqFromBytes(b[32:])
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	if bn128.Fq1.IsZero(x) && bn128.Fq1.IsZero(y) {
+		return [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.One(), bn128.Fq1.Zero()}, nil
+	}
+	p := [3]*big.Int{x, y, bn128.Fq1.One()}
+	if !bn128.g1IsOnCurve(p) {
+		return [3]*big.Int{}, errors.New("G1 point not on the curve")
+	}
+	return p, nil
+}
+
+// G2ToBytes returns the 128 bytes encoding of the G2 point
+func (bn128 Bn128) G2ToBytes(p [3][2]*big.Int) []byte {
+	b := make([]byte, G2Size)
+	if bn128.G2.IsZero(p) {
+		return b
+	}
+	a := bn128.G2.Affine(p)
+	putInt(b[0:], a[0][1])
+	putInt(b[32:], a[0][0])
+	putInt(b[64:], a[1][1])
+	putInt(b[96:], a[1][0])
+	return b
+}
+
+// G2FromBytes decodes the 128 bytes encoding of a G2 point, checking that it
+// is on the curve and in the subgroup of order r
+func (bn128 Bn128) G2FromBytes(b []byte) ([3][2]*big.Int, error) {
+	if len(b) != G2Size {
+		return [3][2]*big.Int{}, errors.New("invalid G2 point length")
+	}
+	var c [4]*big.Int
+	for i := range c {
+		var err error
+		c[i], err = bn128.fqFromBytes(b[i*32:])
+		if err != nil {
+			return [3][2]*big.Int{}, err
+		}
+	}
+	x := [2]*big.Int{c[1], c[0]}
+	y := [2]*big.Int{c[3], c[2]}
+	if bn128.Fq2.IsZero(x) && bn128.Fq2.IsZero(y) {
+		return bn128.G2.Zero(), nil
+	}
+	p := [3][2]*big.Int{x, y, bn128.Fq2.One()}
+	if !bn128.g2IsOnCurve(p) {
+		return [3][2]*big.Int{}, errors.New("G2 point not on the curve")
+	}
+	if !bn128.G2.IsZero(bn128.G2.MulScalar(p, bn128.R)) {
+		return [3][2]*big.Int{}, errors.New("G2 point not in the subgroup")
+	}
+	return p, nil
+}
+
+// g1IsOnCurve checks y^2 = x^3 + 3 for the affine point
+func (bn128 Bn128) g1IsOnCurve(p [3]*big.Int) bool {
+	y2 := bn128.Fq1.Square(p[1])
+	x3b := bn128.Fq1.Add(bn128.Fq1.Mul(bn128.Fq1.Square(p[0]), p[0]), bn128.CoefB)
+	return bn128.Fq1.Equal(y2, x3b)
+}
+
+// g2IsOnCurve checks y^2 = x^3 + 3/(9+u) for the affine point
+func (bn128 Bn128) g2IsOnCurve(p [3][2]*big.Int) bool {
+	y2 := bn128.Fq2.Square(p[1])
+	x3b := bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(p[0]), p[0]), bn128.TwistCoefB)
+	return bn128.Fq2.Equal(y2, x3b)
+}
+
+// PairingCheckBytes is the ecPairing precompiled contract (EIP-197): the input
+// is a list of pairs of encoded G1 and G2 points (192 bytes each), and it
+// returns 1 as a 32 bytes integer if the product of their pairings is one, or 0
+// otherwise. An invalid input returns an error, like a failed call
+func (bn128 Bn128) PairingCheckBytes(input []byte) ([]byte, error) {
+	if len(input)%(G1Size+G2Size) != 0 {
+		return nil, errors.New("invalid pairing input length")
+	}
+	f := bn128.Fq12.One()
+	for i := 0; i < len(input); i += G1Size + G2Size {
+		p1, err := bn128.G1FromBytes(input[i : i+G1Size])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := bn128.G2FromBytes(input[i+G1Size : i+G1Size+G2Size])
+		if err != nil {
+			return nil, err
+		}
+		if bn128.G1.IsZero(p1) || bn128.G2.IsZero(p2) {
+			continue
+		}
+		ml := bn128.MillerLoop(bn128.preComputeG1(p1), bn128.PrecomputeG2(p2))
+		f = bn128.Fq12.Mul(f, ml)
+	}
+	res := make([]byte, 32)
+	if bn128.Fq12.Equal(bn128.finalExponentiation(f), bn128.Fq12.One()) {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// The compressed encoding of the points is the x coordinate (as in the
+// uncompressed encoding), with the two most significant bits (unused, as
+// q < 2^254) as flags: the first one is set when y is the largest of the two
+// square roots, and the second one for the infinity point
+const (
+	// G1CompressedSize is the size of the compressed encoding of a G1 point
+	G1CompressedSize = 32
+	// G2CompressedSize is the size of the compressed encoding of a G2 point
+	G2CompressedSize = 64
+
+	compressedLargestFlag  = 0x80
+	compressedInfinityFlag = 0x40
+	compressedFlags        = compressedLargestFlag | compressedInfinityFlag
+)
+
+// fqIsLargest returns if a > (q-1)/2, that is, if a is the largest of a and -a
+func (bn128 Bn128) fqIsLargest(a *big.Int) bool {
+	half := new(big.Int).Rsh(bn128.Q, 1)
+	return bn128.Fq1.Affine(a).Cmp(half) == 1
+}
+
+// fq2IsLargest compares the imaginary parts, or the real ones if it is zero
+func (bn128 Bn128) fq2IsLargest(a [2]*big.Int) bool {
+	if bn128.Fq1.IsZero(bn128.Fq1.Affine(a[1])) {
+		return bn128.fqIsLargest(a[0])
+	}
+	return bn128.fqIsLargest(a[1])
+}
+
+// G1ToCompressed returns the 32 bytes compressed encoding of the G1 point
+func (bn128 Bn128) G1ToCompressed(p [3]*big.Int) []byte {
+	b := make([]byte, G1CompressedSize)
+	if bn128.G1.IsZero(p) {
+		b[0] = compressedInfinityFlag
+		return b
+	}
+	a := bn128.G1.Affine(p)
+	putInt(b, bn128.Fq1.Affine(a[0]))
+	if bn128.fqIsLargest(a[1]) {
+		b[0] |= compressedLargestFlag
+	}
+	return b
+}
+
+// G1FromCompressed decodes the 32 bytes compressed encoding of a G1 point,
+// checking that x is on the curve
+func (bn128 Bn128) G1FromCompressed(b []byte) ([3]*big.Int, error) {
+	if len(b) != G1CompressedSize {
+		return [3]*big.Int{}, errors.New("invalid compressed G1 point length")
+	}
+	flags := b[0] & compressedFlags
+	xb := append([]byte{b[0] &^ compressedFlags}, b[1:]...)
+	x, err := bn128.fqFromBytes(xb)
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	if flags&compressedInfinityFlag != 0 {
+		if flags != compressedInfinityFlag || !bn128.Fq1.IsZero(x) {
+			return [3]*big.Int{}, errors.New("invalid compressed G1 infinity point")
+		}
+		return [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.One(), bn128.Fq1.Zero()}, nil
+	}
+	y2 := bn128.Fq1.Add(bn128.Fq1.Mul(bn128.Fq1.Square(x), x), bn128.CoefB)
+	y, ok := bn128.Fq1.Sqrt(y2)
+	if !ok {
+		return [3]*big.Int{}, errors.New("G1 point not on the curve")
+	}
+	if bn128.fqIsLargest(y) != (flags&compressedLargestFlag != 0) {
+		y = bn128.Fq1.Affine(bn128.Fq1.Neg(y))
+	}
+	return [3]*big.Int{x, y, bn128.Fq1.One()}, nil
+}
+
+// G2ToCompressed returns the 64 bytes compressed encoding of the G2 point
+func (bn128 Bn128) G2ToCompressed(p [3][2]*big.Int) []byte {
+	b := make([]byte, G2CompressedSize)
+	if bn128.G2.IsZero(p) {
+		b[0] = compressedInfinityFlag
+		return b
+	}
+	a := bn128.G2.Affine(p)
+	putInt(b[0:], a[0][1])
+	putInt(b[32:], a[0][0])
+	if bn128.fq2IsLargest(a[1]) {
+		b[0] |= compressedLargestFlag
+	}
+	return b
+}
+
+// G2FromCompressed decodes the 64 bytes compressed encoding of a G2 point,
+// checking that x is on the curve and the point in the subgroup of order r
+func (bn128 Bn128) G2FromCompressed(b []byte) ([3][2]*big.Int, error) {
+	if len(b) != G2CompressedSize {
+		return [3][2]*big.Int{}, errors.New("invalid compressed G2 point length")
+	}
+	flags := b[0] & compressedFlags
+	x1b := append([]byte{b[0] &^ compressedFlags}, b[1:32]...)
+	x1, err := bn128.fqFromBytes(x1b)
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	x0, err := bn128.fqFromBytes(b[32:])
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	x := [2]*big.Int{x0, x1}
+	if flags&compressedInfinityFlag != 0 {
+		if flags != compressedInfinityFlag || !bn128.Fq2.IsZero(x) {
+			return [3][2]*big.Int{}, errors.New("invalid compressed G2 infinity point")
+		}
+		return bn128.G2.Zero(), nil
+	}
+	y2 := bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.TwistCoefB)
+	y, ok := bn128.Fq2.Sqrt(y2)
+	if !ok {
+		return [3][2]*big.Int{}, errors.New("G2 point not on the curve")
+	}
+	if bn128.fq2IsLargest(y) != (flags&compressedLargestFlag != 0) {
+		y = bn128.Fq2.Affine(bn128.Fq2.Neg(y))
+	}
+	p := [3][2]*big.Int{x, y, bn128.Fq2.One()}
+	if !bn128.G2.IsZero(bn128.G2.MulScalar(p, bn128.R)) {
+		return [3][2]*big.Int{}, errors.New("G2 point not in the subgroup")
+	}
+	return p, nil
+}
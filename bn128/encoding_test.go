@@ -0,0 +1,160 @@
+package bn128
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1Bytes(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	b := bn128.G1ToBytes(bn128.G1.G)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001"+
+		"0000000000000000000000000000000000000000000000000000000000000002", hex.EncodeToString(b))
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33)))
+	p2, err := bn128.G1FromBytes(bn128.G1ToBytes(p))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.Equal(p, p2))
+
+	// infinity
+	zero := bn128.G1.Sub(p, p)
+	assert.Equal(t, make([]byte, G1Size), bn128.G1ToBytes(zero))
+	p2, err = bn128.G1FromBytes(make([]byte, G1Size))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.IsZero(p2))
+
+	// not on the curve
+	b[63] = 3
+	_, err = bn128.G1FromBytes(b)
+	assert.NotNil(t, err)
+	// coordinate not lower than q
+	putInt(b[32:], bn128.Q)
+	_, err = bn128.G1FromBytes(b)
+	assert.NotNil(t, err)
+	_, err = bn128.G1FromBytes(b[:32])
+	assert.NotNil(t, err)
+}
+
+func TestG2Bytes(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// imaginary part first
+	b := bn128.G2ToBytes(bn128.G2.G)
+	assert.Equal(t, "198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2", hex.EncodeToString(b[:32]))
+	assert.Equal(t, "1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed", hex.EncodeToString(b[32:64]))
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	p2, err := bn128.G2FromBytes(bn128.G2ToBytes(p))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G2.Equal(p, p2))
+
+	p2, err = bn128.G2FromBytes(make([]byte, G2Size))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G2.IsZero(p2))
+
+	// swapped real and imaginary parts are not on the curve
+	swapped := append(append(append(append([]byte{}, b[32:64]...), b[:32]...), b[96:]...), b[64:96]...)
+	_, err = bn128.G2FromBytes(swapped)
+	assert.NotNil(t, err)
+}
+
+func TestPairingCheckBytes(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1a := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(25)))
+	g2a := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(30)))
+	g1b := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(30)))
+	g2b := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(25)))
+
+	// e(g1a, g2a) * e(-g1b, g2b) == 1
+	var input []byte
+	input = append(input, bn128.G1ToBytes(g1a)...)
+	input = append(input, bn128.G2ToBytes(g2a)...)
+	input = append(input, bn128.G1ToBytes(bn128.G1.Neg(g1b))...)
+	input = append(input, bn128.G2ToBytes(g2b)...)
+	res, err := bn128.PairingCheckBytes(input)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(int64(1)), new(big.Int).SetBytes(res))
+	assert.Equal(t, 32, len(res))
+
+	// e(g1a, g2a) * e(g1b, g2b) != 1
+	copy(input[G1Size+G2Size:], bn128.G1ToBytes(g1b))
+	res, err = bn128.PairingCheckBytes(input)
+	assert.Nil(t, err)
+	assert.Equal(t, make([]byte, 32), res)
+
+	// empty input, and pairs with the infinity point
+	res, err = bn128.PairingCheckBytes(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(int64(1)), new(big.Int).SetBytes(res))
+	res, err = bn128.PairingCheckBytes(append(make([]byte, G1Size), bn128.G2ToBytes(g2a)...))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(int64(1)), new(big.Int).SetBytes(res))
+
+	_, err = bn128.PairingCheckBytes(input[:G1Size+G2Size+1])
+	assert.NotNil(t, err)
+}
+
+func TestCompressed(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	for i := 1; i < 6; i++ {
+		p1 := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(i*1000+7)))
+		b := bn128.G1ToCompressed(p1)
+		assert.Equal(t, G1CompressedSize, len(b))
+		d1, err := bn128.G1FromCompressed(b)
+		assert.Nil(t, err)
+		assert.True(t, bn128.G1.Equal(p1, d1))
+		// the negated point only differs in the flag
+		nb := bn128.G1ToCompressed(bn128.G1.Neg(p1))
+		assert.Equal(t, b[0]^compressedLargestFlag, nb[0])
+		assert.Equal(t, b[1:], nb[1:])
+
+		p2 := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(i*1000+7)))
+		b = bn128.G2ToCompressed(p2)
+		assert.Equal(t, G2CompressedSize, len(b))
+		d2, err := bn128.G2FromCompressed(b)
+		assert.Nil(t, err)
+		assert.True(t, bn128.G2.Equal(p2, d2))
+		d2, err = bn128.G2FromCompressed(bn128.G2ToCompressed(bn128.G2.Neg(p2)))
+		assert.Nil(t, err)
+		assert.True(t, bn128.G2.Equal(bn128.G2.Neg(p2), d2))
+	}
+
+	// infinity
+	d1, err := bn128.G1FromCompressed(bn128.G1ToCompressed(bn128.G1.Sub(bn128.G1.G, bn128.G1.G)))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.IsZero(d1))
+	d2, err := bn128.G2FromCompressed(bn128.G2ToCompressed(bn128.G2.Zero()))
+	assert.Nil(t, err)
+	assert.True(t, bn128.G2.IsZero(d2))
+
+	// x = 0 is not on the G1 curve (3 is not a square)
+	_, err = bn128.G1FromCompressed(make([]byte, G1CompressedSize))
+	assert.NotNil(t, err)
+
+	// a point of the twist curve which is not in the subgroup of order r
+	found := false
+	for i := int64(1); i < 20 && !found; i++ {
+		x := [2]*big.Int{big.NewInt(i), big.NewInt(int64(1))}
+		y2 := bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.TwistCoefB)
+		if _, ok := bn128.Fq2.Sqrt(y2); !ok {
+			continue
+		}
+		found = true
+		b := make([]byte, G2CompressedSize)
+		putInt(b[0:], x[1])
+		putInt(b[32:], x[0])
+		_, err = bn128.G2FromCompressed(b)
+		assert.Equal(t, "G2 point not in the subgroup", err.Error())
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,191 @@
+package bn128
+
+import (
+	"math/big"
+)
+
+// fixedBaseWindow is the window size in bits of the fixed-base tables
+const fixedBaseWindow = 5
+
+// The fixed-base tables store, for each window i of fixedBaseWindow bits, the
+// odd multiples (2j+1) * 2^(w*i) * P for j in [0, 2^(w-1)). The scalar is made
+// odd (adding R if it is even) and recoded into signed odd digits
+// (https://eprint.iacr.org/2009/058.pdf, regular recoding), so p*e is the sum
+// of one table point, negated or not, for each window. The table point of a
+// digit is selected reading all the points of the row with conditional swaps,
+// and the sum uses a complete addition (valid also for equal points and the
+// infinity point), so the sequence of operations and the table accesses are
+// the same whatever the value of e, without doublings. Like the ladder, it is
+// not constant time at the big.Int level.
+
+// fixedBaseGroup are the point operations used by the fixed-base tables
+type fixedBaseGroup[P any] interface {
+	Double(p P) P
+	addComplete(p1, p2 P) P
+	cneg(p P, b uint) P
+	cswap(p1, p2 P, b uint) (P, P)
+}
+
+type fixedBaseTable[P any] struct {
+	order  *big.Int
+	points [][]P
+}
+
+// newFixedBaseTable precomputes the table of p, for the scalars modulo order
+func newFixedBaseTable[P any](g fixedBaseGroup[P], p P, order *big.Int) fixedBaseTable[P] {
+	// the odd scalar has up to order.BitLen()+1 bits, and the last digit
+	// (positive) takes one more window
+	windows := (order.BitLen()+1+fixedBaseWindow-1)/fixedBaseWindow + 1
+	t := fixedBaseTable[P]{order: order}
+	base := p
+	for i := 0; i < windows; i++ {
+		row := []P{base}
+		base2 := g.Double(base)
+		for j := 1; j < 1<<(fixedBaseWindow-1); j++ {
+			row = append(row, g.addComplete(row[j-1], base2))
+		}
+		t.points = append(t.points, row)
+		for j := 0; j < fixedBaseWindow; j++ {
+			base = g.Double(base)
+		}
+	}
+	return t
+}
+
+// recode returns the signed odd digits of (e mod order), or of (e mod order)
+// + order if it is even, in [-(2^w-1), 2^w-1]
+func (t fixedBaseTable[P]) recode(e *big.Int) []int64 {
+	k := new(big.Int).Mod(e, t.order)
+	// add the order if k is even, without branching
+	even := big.NewInt(int64(1 - k.Bit(0)))
+	k.Add(k, even.Mul(even, t.order))
+
+	mask := big.NewInt(int64(1<<(fixedBaseWindow+1) - 1))
+	half := int64(1 << fixedBaseWindow)
+	digits := make([]int64, len(t.points))
+	for i := 0; i < len(digits)-1; i++ {
+		// d = (k mod 2^(w+1)) - 2^w, which is odd, and k = (k - d) / 2^w
+		// is odd again
+		d := new(big.Int).And(k, mask).Int64() - half
+		digits[i] = d
+		k.Sub(k, big.NewInt(d))
+		k.Rsh(k, fixedBaseWindow)
+	}
+	digits[len(digits)-1] = k.Int64()
+	return digits
+}
+
+// mulScalar returns p*e as the sum of the table points of the digits of e
+func (t fixedBaseTable[P]) mulScalar(g fixedBaseGroup[P], e *big.Int) P {
+	digits := t.recode(e)
+	var q P
+	for i, d := range digits {
+		// |d| = 2j+1, and the sign bit
+		neg := uint(uint64(d) >> 63)
+		abs := d * (1 - 2*int64(neg))
+		p := g.cneg(lookup(g, t.points[i], abs>>1), neg)
+		if i == 0 {
+			q = p
+			continue
+		}
+		q = g.addComplete(q, p)
+	}
+	return q
+}
+
+// lookup returns row[idx], reading all the points of the row and selecting it
+// with conditional swaps, so the accesses do not depend on idx
+func lookup[P any](g fixedBaseGroup[P], row []P, idx int64) P {
+	p := row[0]
+	for j := 1; j < len(row); j++ {
+		// eq is 1 if j = idx, without branching
+		d := uint64(int64(j) ^ idx)
+		eq := uint(1 - (d|-d)>>63)
+		p, _ = g.cswap(p, row[j], eq)
+	}
+	return p
+}
+
+// zeroBit returns 1 if the (reduced) field element is zero, and 0 otherwise
+func zeroBit(a *big.Int) uint {
+	return uint(1 - a.Sign()*a.Sign())
+}
+
+// G1Table is a precomputed table of multiples of a fixed G1 point, to
+// multiply it by many scalars with only additions
+type G1Table struct {
+	g1    G1
+	table fixedBaseTable[[3]*big.Int]
+}
+
+// NewTable precomputes the G1Table of the point p (usually the generator G),
+// which must be in the subgroup of order R
+func (g1 G1) NewTable(p [3]*big.Int) G1Table {
+	return G1Table{g1, newFixedBaseTable[[3]*big.Int](g1, p, g1.R)}
+}
+
+// MulScalar returns the point of the table multiplied by the scalar
+func (t G1Table) MulScalar(e *big.Int) [3]*big.Int {
+	return t.table.mulScalar(t.g1, e)
+}
+
+// addComplete returns p1 + p2 for any points, also for p1 = p2 and the
+// infinity point: it computes both the addition and the doubling, and selects
+// the result with conditional swaps, with the same operations in all the cases
+func (g1 G1) addComplete(p1, p2 [3]*big.Int) [3]*big.Int {
+	sum, h, r := g1.add(p1, p2)
+	dbl := g1.double(p1)
+	zero1, zero2 := zeroBit(p1[2]), zeroBit(p2[2])
+	// p1 = p2 (and not the infinity point) when h = 0 and r = 0
+	same := zeroBit(h) & zeroBit(r) & (1 - zero1) & (1 - zero2)
+	res, _ := g1.cswap(sum, dbl, same)
+	res, _ = g1.cswap(res, p1, zero2)
+	res, _ = g1.cswap(res, p2, zero1)
+	return res
+}
+
+// cneg negates the point if b is 1, with the same operations for b = 0
+func (g1 G1) cneg(p [3]*big.Int, b uint) [3]*big.Int {
+	s := g1.F.Sub(g1.F.One(), big.NewInt(int64(2*b)))
+	return [3]*big.Int{p[0], g1.F.Mul(p[1], s), p[2]}
+}
+
+// G2Table is a precomputed table of multiples of a fixed G2 point, to
+// multiply it by many scalars with only additions
+type G2Table struct {
+	g2    G2
+	table fixedBaseTable[[3][2]*big.Int]
+}
+
+// NewTable precomputes the G2Table of the point p (usually the generator G),
+// which must be in the subgroup of order R
+func (g2 G2) NewTable(p [3][2]*big.Int) G2Table {
+	return G2Table{g2, newFixedBaseTable[[3][2]*big.Int](g2, p, g2.R)}
+}
+
+// MulScalar returns the point of the table multiplied by the scalar
+func (t G2Table) MulScalar(e *big.Int) [3][2]*big.Int {
+	return t.table.mulScalar(t.g2, e)
+}
+
+// addComplete returns p1 + p2 for any points, also for p1 = p2 and the
+// infinity point, as G1.addComplete
+func (g2 G2) addComplete(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
+	sum, h, r := g2.add(p1, p2)
+	dbl := g2.double(p1)
+	zero1 := zeroBit(p1[2][0]) & zeroBit(p1[2][1])
+	zero2 := zeroBit(p2[2][0]) & zeroBit(p2[2][1])
+	// p1 = p2 (and not the infinity point) when h = 0 and r = 0
+	same := zeroBit(h[0]) & zeroBit(h[1]) & zeroBit(r[0]) & zeroBit(r[1]) & (1 - zero1) & (1 - zero2)
+	res, _ := g2.cswap(sum, dbl, same)
+	res, _ = g2.cswap(res, p1, zero2)
+	res, _ = g2.cswap(res, p2, zero1)
+	return res
+}
+
+// cneg negates the point if b is 1, with the same operations for b = 0
+func (g2 G2) cneg(p [3][2]*big.Int, b uint) [3][2]*big.Int {
+	s := g2.F.F.Sub(g2.F.F.One(), big.NewInt(int64(2*b)))
+	y := [2]*big.Int{g2.F.F.Mul(p[1][0], s), g2.F.F.Mul(p[1][1], s)}
+	return [3][2]*big.Int{p[0], y, p[2]}
+}
@@ -9,6 +9,11 @@ import (
 type G1 struct {
 	F fields.Fq
 	G [3]*big.Int
+	R *big.Int // order of G, used by MulScalarConstantTime
+
+	// beta is the cube root of unity of the endomorphism (x, y) -> (beta*x, y)
+	beta *big.Int
+	glv  glv
 }
 
 func NewG1(f fields.Fq, g [2]*big.Int) G1 {
@@ -22,8 +27,9 @@ func NewG1(f fields.Fq, g [2]*big.Int) G1 {
 	return g1
 }
 
-func (g1 G1) Zero() [2]*big.Int {
-	return [2]*big.Int{g1.F.Zero(), g1.F.Zero()}
+// Zero returns the point at infinity in Jacobian coordinates
+func (g1 G1) Zero() [3]*big.Int {
+	return [3]*big.Int{g1.F.Zero(), g1.F.One(), g1.F.Zero()}
 }
 func (g1 G1) IsZero(p [3]*big.Int) bool {
 	return g1.F.IsZero(p[2])
@@ -41,7 +47,14 @@ func (g1 G1) Add(p1, p2 [3]*big.Int) [3]*big.Int {
 	if g1.IsZero(p2) {
 		return p1
 	}
+	p, _, _ := g1.add(p1, p2)
+	return p
+}
 
+// add returns p1 + p2 with the addition formulas, which are not valid for
+// p1 = p2 nor the infinity point, and the values h and r of the formulas (both
+// are zero when p1 = p2)
+func (g1 G1) add(p1, p2 [3]*big.Int) ([3]*big.Int, *big.Int, *big.Int) {
 	x1 := p1[0]
 	y1 := p1[1]
 	z1 := p1[2]
@@ -85,7 +98,7 @@ func (g1 G1) Add(p1, p2 [3]*big.Int) [3]*big.Int {
 	t14 := g1.F.Sub(t13, z2z2)
 	z3 := g1.F.Mul(t14, h)
 
-	return [3]*big.Int{x3, y3, z3}
+	return [3]*big.Int{x3, y3, z3}, h, r
 }
 
 func (g1 G1) Neg(p [3]*big.Int) [3]*big.Int {
@@ -107,7 +120,12 @@ func (g1 G1) Double(p [3]*big.Int) [3]*big.Int {
 	if g1.IsZero(p) {
 		return p
 	}
+	return g1.double(p)
+}
 
+// double returns 2p with the doubling formulas, which give a point with z = 0
+// for the infinity point
+func (g1 G1) double(p [3]*big.Int) [3]*big.Int {
 	a := g1.F.Square(p[0])
 	b := g1.F.Square(p[1])
 	c := g1.F.Square(b)
@@ -154,9 +172,38 @@ func (g1 G1) MulScalar(p [3]*big.Int, e *big.Int) [3]*big.Int {
 	return q
 }
 
+// endomorphism returns (beta*x, y, z), which is Lambda*p
+func (g1 G1) endomorphism(p [3]*big.Int) [3]*big.Int {
+	return [3]*big.Int{g1.F.Mul(p[0], g1.beta), p[1], p[2]}
+}
+
+// MulScalarGLV multiplies the point by the scalar with the GLV method, with
+// the scalar split into two of half the size. It is not constant time
+func (g1 G1) MulScalarGLV(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	k1, k2 := g1.glv.decompose(e)
+	return jointMulScalar[[3]*big.Int](g1, g1.Zero(), p, g1.endomorphism(p), k1, k2)
+}
+
+// MulScalarConstantTime multiplies the point by the scalar with a Montgomery
+// ladder, doing the same operations for any value of the scalar, to be used
+// with secret scalars. The point must be in the subgroup of order R
+func (g1 G1) MulScalarConstantTime(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	return montgomeryLadder[[3]*big.Int](g1, p, e, g1.R)
+}
+
+// cswap swaps the points if b is 1, with the same operations for b = 0
+func (g1 G1) cswap(p1, p2 [3]*big.Int, b uint) ([3]*big.Int, [3]*big.Int) {
+	bit := big.NewInt(int64(b))
+	var r1, r2 [3]*big.Int
+	for i := range p1 {
+		r1[i], r2[i] = cswapInt(g1.F, p1[i], p2[i], bit)
+	}
+	return r1, r2
+}
+
 func (g1 G1) Affine(p [3]*big.Int) [2]*big.Int {
 	if g1.IsZero(p) {
-		return g1.Zero()
+		return [2]*big.Int{g1.F.Zero(), g1.F.Zero()}
 	}
 
 	zinv := g1.F.Inverse(p[2])
@@ -169,6 +216,31 @@ func (g1 G1) Affine(p [3]*big.Int) [2]*big.Int {
 	return [2]*big.Int{x, y}
 }
 
+// BatchAffine returns the points normalized to z = 1 (the zero points are not
+// modified), with only one inversion for all of them
+func (g1 G1) BatchAffine(ps [][3]*big.Int) [][3]*big.Int {
+	var zs []*big.Int
+	for _, p := range ps {
+		zs = append(zs, p[2])
+	}
+	zinvs := g1.F.BatchInverse(zs)
+	var r [][3]*big.Int
+	for i, p := range ps {
+		if g1.IsZero(p) {
+			r = append(r, p)
+			continue
+		}
+		zinv2 := g1.F.Square(zinvs[i])
+		zinv3 := g1.F.Mul(zinv2, zinvs[i])
+		r = append(r, [3]*big.Int{
+			g1.F.Mul(p[0], zinv2),
+			g1.F.Mul(p[1], zinv3),
+			g1.F.One(),
+		})
+	}
+	return r
+}
+
 func (g1 G1) Equal(p1, p2 [3]*big.Int) bool {
 	if g1.IsZero(p1) {
 		return g1.IsZero(p2)
@@ -2,6 +2,7 @@ package bn128
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -29,3 +30,280 @@ func TestG1(t *testing.T) {
 	assert.Equal(t, "2f978c0ab89ebaa576866706b14787f360c4d6c3869efe5a72f7c3651a72ff00", hex.EncodeToString(a[0].Bytes()))
 	assert.Equal(t, "12e4ba7f0edca8b4fa668fe153aebd908d322dc26ad964d4cd314795844b62b2", hex.EncodeToString(a[1].Bytes()))
 }
+
+func TestG1BatchAffine(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	var ps [][3]*big.Int
+	for i := 1; i < 6; i++ {
+		ps = append(ps, bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(i*11))))
+	}
+	ps = append(ps, bn128.G1.Sub(ps[0], ps[0]))
+	affine := bn128.G1.BatchAffine(ps)
+	assert.Equal(t, len(ps), len(affine))
+	for i := range ps[:5] {
+		assert.True(t, bn128.G1.Equal(ps[i], affine[i]))
+		assert.Equal(t, bn128.G1.Affine(ps[i]), [2]*big.Int{affine[i][0], affine[i][1]})
+		assert.Equal(t, big.NewInt(int64(1)), affine[i][2])
+	}
+	assert.True(t, bn128.G1.IsZero(affine[5]))
+}
+
+// opRecorder wraps the point operations of the Montgomery ladder, recording
+// their sequence, and if they get the infinity point (where Add and Double
+// take a different path)
+type opRecorder[P any] struct {
+	g      ladderGroup[P]
+	isZero func(P) bool
+	ops    *[]string
+}
+
+func (r opRecorder[P]) record(op string, ps ...P) {
+	for _, p := range ps {
+		if r.isZero(p) {
+			op += "-zero"
+		}
+	}
+	*r.ops = append(*r.ops, op)
+}
+func (r opRecorder[P]) Add(p1, p2 P) P {
+	r.record("add", p1, p2)
+	return r.g.Add(p1, p2)
+}
+func (r opRecorder[P]) Double(p P) P {
+	r.record("double", p)
+	return r.g.Double(p)
+}
+func (r opRecorder[P]) cswap(p1, p2 P, b uint) (P, P) {
+	r.record("cswap")
+	return r.g.cswap(p1, p2, b)
+}
+
+// ladderOps returns the operations done by the ladder for each scalar, which
+// must be the same for all of them
+func ladderOps[P any](g ladderGroup[P], isZero func(P) bool, p P, order *big.Int, scalars []*big.Int) [][]string {
+	var res [][]string
+	for _, e := range scalars {
+		var ops []string
+		montgomeryLadder[P](opRecorder[P]{g, isZero, &ops}, p, e, order)
+		res = append(res, ops)
+	}
+	return res
+}
+
+// testScalars returns scalars for the ladder, without -2, -1, 0, 1 mod R,
+// where the ladder gets the infinity point
+func testScalars(t *testing.T, bn128 Bn128) []*big.Int {
+	scalars := []*big.Int{
+		big.NewInt(int64(2)),
+		big.NewInt(int64(3)),
+		new(big.Int).Sub(bn128.R, big.NewInt(int64(3))),
+		new(big.Int).Lsh(big.NewInt(int64(1)), 200),
+		new(big.Int).Add(bn128.R, big.NewInt(int64(5))),
+	}
+	fqR, err := NewFqR()
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		r, err := fqR.Rand()
+		assert.Nil(t, err)
+		scalars = append(scalars, r)
+	}
+	return scalars
+}
+
+func TestG1MulScalarConstantTime(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	scalars := testScalars(t, bn128)
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(7)))
+	assert.True(t, bn128.G1.IsZero(bn128.G1.MulScalarConstantTime(p, big.NewInt(int64(0)))))
+	assert.True(t, bn128.G1.Equal(p, bn128.G1.MulScalarConstantTime(p, big.NewInt(int64(1)))))
+	assert.True(t, bn128.G1.Equal(bn128.G1.Neg(p), bn128.G1.MulScalarConstantTime(p, big.NewInt(int64(-1)))))
+	for _, e := range scalars {
+		expected := bn128.G1.MulScalar(p, new(big.Int).Mod(e, bn128.R))
+		assert.True(t, bn128.G1.Equal(expected, bn128.G1.MulScalarConstantTime(p, e)))
+	}
+
+	ops := ladderOps[[3]*big.Int](bn128.G1, bn128.G1.IsZero, p, bn128.R, scalars)
+	assert.Equal(t, 1+4*bn128.R.BitLen(), len(ops[0]))
+	for i := range ops {
+		assert.Equal(t, ops[0], ops[i])
+	}
+}
+
+func TestG1MulScalarGLV(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// the basis vectors are in the lattice
+	g := bn128.G1.glv
+	for _, v := range [][2]*big.Int{g.v1, g.v2} {
+		a := new(big.Int).Add(v[0], new(big.Int).Mul(v[1], g.Lambda))
+		assert.Equal(t, 0, new(big.Int).Mod(a, bn128.R).Sign())
+	}
+	assert.Equal(t, 0, new(big.Int).Abs(g.det).Cmp(bn128.R))
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(7)))
+	assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, g.Lambda), bn128.G1.endomorphism(p)))
+
+	scalars := append(testScalars(t, bn128), big.NewInt(int64(0)), big.NewInt(int64(1)), new(big.Int).Sub(bn128.R, big.NewInt(int64(1))))
+	for _, e := range scalars {
+		k1, k2 := g.decompose(e)
+		assert.True(t, k1.BitLen() <= 128)
+		assert.True(t, k2.BitLen() <= 128)
+		k := new(big.Int).Add(k1, new(big.Int).Mul(k2, g.Lambda))
+		assert.Equal(t, new(big.Int).Mod(e, bn128.R), k.Mod(k, bn128.R))
+
+		expected := bn128.G1.MulScalar(p, new(big.Int).Mod(e, bn128.R))
+		assert.True(t, bn128.G1.Equal(expected, bn128.G1.MulScalarGLV(p, e)))
+	}
+
+	// the joint multiplication of p and c*p, where the accumulated point
+	// meets p1, p2, p1 + p2 or their negatives
+	for _, c := range []*big.Int{big.NewInt(int64(1)), new(big.Int).Sub(bn128.R, big.NewInt(int64(1))), g.Lambda} {
+		p2 := bn128.G1.MulScalar(p, c)
+		for _, k := range testJointScalars() {
+			k1, k2 := big.NewInt(k[0]), big.NewInt(k[1])
+			e := new(big.Int).Add(k1, new(big.Int).Mul(k2, c))
+			expected := bn128.G1.MulScalar(p, e.Mod(e, bn128.R))
+			assert.True(t, bn128.G1.Equal(expected, jointMulScalar[[3]*big.Int](bn128.G1, bn128.G1.Zero(), p, p2, k1, k2)), "%s %d %d", c, k[0], k[1])
+		}
+	}
+}
+
+// testJointScalars returns scalars k1, k2 with k1 = ±k2, and k2 = 0 or 1
+func testJointScalars() [][2]int64 {
+	return [][2]int64{{1, 1}, {3, 3}, {6, 6}, {-5, 5}, {1, 0}, {0, 1}, {0, 0}, {7, 0}, {7, 1}, {2, 1}, {-1, 1}}
+}
+
+// tableOps returns the operations done by the fixed-base table for each
+// scalar, which must be the same for all of them
+func tableOps[P any](g fixedBaseGroup[P], isZero func(P) bool, t fixedBaseTable[P], scalars []*big.Int) [][]string {
+	var res [][]string
+	for _, e := range scalars {
+		var ops []string
+		t.mulScalar(tableRecorder[P]{g, opRecorder[P]{isZero: isZero, ops: &ops}}, e)
+		res = append(res, ops)
+	}
+	return res
+}
+
+type tableRecorder[P any] struct {
+	g fixedBaseGroup[P]
+	r opRecorder[P]
+}
+
+func (r tableRecorder[P]) Double(p P) P {
+	r.r.record("double", p)
+	return r.g.Double(p)
+}
+
+// addComplete is recorded without the infinity points, as it does the same
+// operations for them
+func (r tableRecorder[P]) addComplete(p1, p2 P) P {
+	r.r.record("add")
+	return r.g.addComplete(p1, p2)
+}
+func (r tableRecorder[P]) cneg(p P, b uint) P {
+	r.r.record("cneg")
+	return r.g.cneg(p, b)
+}
+func (r tableRecorder[P]) cswap(p1, p2 P, b uint) (P, P) {
+	r.r.record("cswap")
+	return r.g.cswap(p1, p2, b)
+}
+
+// tableEdgeScalars returns the scalars where the recoding or the partial sums
+// of the fixed-base table get special values: 0, 1, R-1, the values around
+// the windows of fixedBaseWindow bits, and their negations
+func tableEdgeScalars(bn128 Bn128) []*big.Int {
+	var scalars []*big.Int
+	for _, k := range []int64{0, 1, 2, 3} {
+		scalars = append(scalars, big.NewInt(k), new(big.Int).Sub(bn128.R, big.NewInt(k+1)))
+	}
+	for _, bits := range []uint{fixedBaseWindow - 1, fixedBaseWindow, fixedBaseWindow + 1, 2 * fixedBaseWindow, 250, 253} {
+		w := new(big.Int).Lsh(big.NewInt(int64(1)), bits)
+		for _, d := range []int64{-1, 0, 1} {
+			k := new(big.Int).Add(w, big.NewInt(d))
+			scalars = append(scalars, k, new(big.Int).Sub(bn128.R, k))
+		}
+	}
+	return scalars
+}
+
+func TestG1Table(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	table := bn128.G1.NewTable(bn128.G1.G)
+	scalars := append(testScalars(t, bn128), tableEdgeScalars(bn128)...)
+	for _, e := range scalars {
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(bn128.G1.G, e), table.MulScalar(e)), e.String())
+	}
+	assert.True(t, bn128.G1.IsZero(table.MulScalar(big.NewInt(int64(0)))))
+	assert.True(t, bn128.G1.IsZero(table.MulScalar(bn128.R)))
+
+	ops := tableOps[[3]*big.Int](bn128.G1, bn128.G1.IsZero, table.table, scalars)
+	windows := len(table.table.points)
+	assert.Equal(t, windows<<(fixedBaseWindow-1)+windows-1, len(ops[0]))
+	for i := range ops {
+		assert.Equal(t, ops[0], ops[i])
+	}
+}
+
+func TestG1AddComplete(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(7)))
+	q := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(9)))
+	zero := bn128.G1.Zero()
+	assert.True(t, bn128.G1.IsZero(zero))
+	assert.True(t, bn128.G1.Equal(p, bn128.G1.Add(p, zero)))
+	assert.Equal(t, [2]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero()}, bn128.G1.Affine(zero))
+	assert.True(t, bn128.G1.Equal(bn128.G1.Add(p, q), bn128.G1.addComplete(p, q)))
+	assert.True(t, bn128.G1.Equal(bn128.G1.Double(p), bn128.G1.addComplete(p, p)))
+	// the same point with another z
+	assert.True(t, bn128.G1.Equal(bn128.G1.Double(p), bn128.G1.addComplete(p, bn128.G1.Add(q, bn128.G1.Neg(bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(2))))))))
+	assert.True(t, bn128.G1.IsZero(bn128.G1.addComplete(p, bn128.G1.Neg(p))))
+	assert.True(t, bn128.G1.Equal(p, bn128.G1.addComplete(p, zero)))
+	assert.True(t, bn128.G1.Equal(p, bn128.G1.addComplete(zero, p)))
+	assert.True(t, bn128.G1.IsZero(bn128.G1.addComplete(zero, zero)))
+}
+
+func TestG1Points(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33)))
+	jac := G1JacFromArray(p)
+	affine := jac.Affine()
+	assert.Equal(t, bn128.G1.Affine(p), [2]*big.Int{affine.X, affine.Y})
+	assert.True(t, affine.Jac().Equal(jac))
+	assert.True(t, G1AffineFromArray(affine.Array()).Equal(affine))
+	assert.True(t, affine.IsOnCurve())
+	assert.True(t, affine.IsInSubgroup())
+	assert.True(t, jac.IsInSubgroup())
+	assert.False(t, G1Affine{affine.X, affine.X}.IsOnCurve())
+	assert.Equal(t, "("+affine.X.String()+", "+affine.Y.String()+")", affine.String())
+
+	inf := G1JacFromArray([3]*big.Int{bn128.Fq1.One(), bn128.Fq1.One(), bn128.Fq1.Zero()})
+	assert.True(t, inf.IsInfinity())
+	assert.True(t, inf.Affine().IsInfinity())
+	assert.True(t, G1Affine{}.Equal(inf.Affine()))
+	assert.True(t, bn128.G1.IsZero(G1Affine{}.Array()))
+	assert.Equal(t, "infinity", inf.String())
+
+	affines := G1AffinesFromArrays([][3]*big.Int{p, inf.Array()})
+	assert.True(t, affines[0].Equal(affine))
+	assert.True(t, affines[1].IsInfinity())
+
+	b, err := json.Marshal(jac)
+	assert.Nil(t, err)
+	var decoded G1Affine
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.True(t, decoded.Equal(affine))
+	assert.NotNil(t, json.Unmarshal([]byte(`"00"`), &decoded))
+}
@@ -9,6 +9,13 @@ import (
 type G2 struct {
 	F fields.Fq2
 	G [3][2]*big.Int
+	R *big.Int // order of G, used by MulScalarConstantTime
+
+	// psiX, psiY are the coefficients of the untwist-Frobenius-twist
+	// endomorphism (x, y) -> (psiX*conj(x), psiY*conj(y))
+	psiX [2]*big.Int
+	psiY [2]*big.Int
+	glv  glv
 }
 
 func NewG2(f fields.Fq2, g [2][2]*big.Int) G2 {
@@ -41,7 +48,14 @@ func (g2 G2) Add(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p2) {
 		return p1
 	}
+	p, _, _ := g2.add(p1, p2)
+	return p
+}
 
+// add returns p1 + p2 with the addition formulas, which are not valid for
+// p1 = p2 nor the infinity point, and the values h and r of the formulas (both
+// are zero when p1 = p2)
+func (g2 G2) add(p1, p2 [3][2]*big.Int) ([3][2]*big.Int, [2]*big.Int, [2]*big.Int) {
 	x1 := p1[0]
 	y1 := p1[1]
 	z1 := p1[2]
@@ -85,7 +99,7 @@ func (g2 G2) Add(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
 	t14 := g2.F.Sub(t13, z2z2)
 	z3 := g2.F.Mul(t14, h)
 
-	return [3][2]*big.Int{x3, y3, z3}
+	return [3][2]*big.Int{x3, y3, z3}, h, r
 }
 
 func (g2 G2) Neg(p [3][2]*big.Int) [3][2]*big.Int {
@@ -109,7 +123,12 @@ func (g2 G2) Double(p [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p) {
 		return p
 	}
+	return g2.double(p)
+}
 
+// double returns 2p with the doubling formulas, which give a point with z = 0
+// for the infinity point
+func (g2 G2) double(p [3][2]*big.Int) [3][2]*big.Int {
 	a := g2.F.Square(p[0])
 	b := g2.F.Square(p[1])
 	c := g2.F.Square(b)
@@ -180,6 +199,44 @@ func (g2 G2) MulScalar(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
 	return q
 }
 
+// endomorphism returns the untwist-Frobenius-twist of p, which is (Q mod R)*p
+// for the points of the subgroup of order R
+func (g2 G2) endomorphism(p [3][2]*big.Int) [3][2]*big.Int {
+	return [3][2]*big.Int{
+		g2.F.Mul(g2.F.Frobenius(p[0], 1), g2.psiX),
+		g2.F.Mul(g2.F.Frobenius(p[1], 1), g2.psiY),
+		g2.F.Frobenius(p[2], 1),
+	}
+}
+
+// MulScalarGLS multiplies the point by the scalar with the GLS method (GLV
+// with the endomorphism from the Frobenius), with the scalar split into two of
+// half the size. The point must be in the subgroup of order R, and it is not
+// constant time
+func (g2 G2) MulScalarGLS(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	k1, k2 := g2.glv.decompose(e)
+	return jointMulScalar[[3][2]*big.Int](g2, g2.Zero(), p, g2.endomorphism(p), k1, k2)
+}
+
+// MulScalarConstantTime multiplies the point by the scalar with a Montgomery
+// ladder, doing the same operations for any value of the scalar, to be used
+// with secret scalars. The point must be in the subgroup of order R
+func (g2 G2) MulScalarConstantTime(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	return montgomeryLadder[[3][2]*big.Int](g2, p, e, g2.R)
+}
+
+// cswap swaps the points if b is 1, with the same operations for b = 0
+func (g2 G2) cswap(p1, p2 [3][2]*big.Int, b uint) ([3][2]*big.Int, [3][2]*big.Int) {
+	bit := big.NewInt(int64(b))
+	var r1, r2 [3][2]*big.Int
+	for i := range p1 {
+		for j := range p1[i] {
+			r1[i][j], r2[i][j] = cswapInt(g2.F.F, p1[i][j], p2[i][j], bit)
+		}
+	}
+	return r1, r2
+}
+
 func (g2 G2) Affine(p [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p) {
 		return g2.Zero()
@@ -199,6 +256,31 @@ func (g2 G2) Affine(p [3][2]*big.Int) [3][2]*big.Int {
 	}
 }
 
+// BatchAffine returns the points normalized to z = 1, as Affine, with only one
+// inversion for all of them
+func (g2 G2) BatchAffine(ps [][3][2]*big.Int) [][3][2]*big.Int {
+	var zs [][2]*big.Int
+	for _, p := range ps {
+		zs = append(zs, p[2])
+	}
+	zinvs := g2.F.BatchInverse(zs)
+	var r [][3][2]*big.Int
+	for i, p := range ps {
+		if g2.IsZero(p) {
+			r = append(r, g2.Zero())
+			continue
+		}
+		zinv2 := g2.F.Square(zinvs[i])
+		zinv3 := g2.F.Mul(zinv2, zinvs[i])
+		r = append(r, [3][2]*big.Int{
+			g2.F.Affine(g2.F.Mul(p[0], zinv2)),
+			g2.F.Affine(g2.F.Mul(p[1], zinv3)),
+			g2.F.One(),
+		})
+	}
+	return r
+}
+
 func (g2 G2) Equal(p1, p2 [3][2]*big.Int) bool {
 	if g2.IsZero(p1) {
 		return g2.IsZero(p2)
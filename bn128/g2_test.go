@@ -1,6 +1,7 @@
 package bn128
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -22,3 +23,153 @@ func TestG2(t *testing.T) {
 	grsum2 := bn128.G2.Affine(bn128.G2.MulScalar(bn128.G2.G, r1r2))
 	assert.True(t, bn128.G2.Equal(grsum1, grsum2))
 }
+
+func TestG2BatchAffine(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	var ps [][3][2]*big.Int
+	for i := 1; i < 6; i++ {
+		ps = append(ps, bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(i*11))))
+	}
+	ps = append(ps, bn128.G2.Zero())
+	affine := bn128.G2.BatchAffine(ps)
+	assert.Equal(t, len(ps), len(affine))
+	for i := range ps[:5] {
+		assert.True(t, bn128.G2.Equal(ps[i], affine[i]))
+		assert.Equal(t, bn128.G2.Affine(ps[i]), affine[i])
+	}
+	assert.True(t, bn128.G2.IsZero(affine[5]))
+}
+
+func TestG2MulScalarConstantTime(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	scalars := testScalars(t, bn128)
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(7)))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.MulScalarConstantTime(p, big.NewInt(int64(0)))))
+	assert.True(t, bn128.G2.Equal(p, bn128.G2.MulScalarConstantTime(p, big.NewInt(int64(1)))))
+	assert.True(t, bn128.G2.Equal(bn128.G2.Neg(p), bn128.G2.MulScalarConstantTime(p, big.NewInt(int64(-1)))))
+	for _, e := range scalars {
+		expected := bn128.G2.MulScalar(p, new(big.Int).Mod(e, bn128.R))
+		assert.True(t, bn128.G2.Equal(expected, bn128.G2.MulScalarConstantTime(p, e)))
+	}
+
+	ops := ladderOps[[3][2]*big.Int](bn128.G2, bn128.G2.IsZero, p, bn128.R, scalars)
+	for i := range ops {
+		assert.Equal(t, ops[0], ops[i])
+	}
+}
+
+func TestG2MulScalarGLS(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g := bn128.G2.glv
+	for _, v := range [][2]*big.Int{g.v1, g.v2} {
+		a := new(big.Int).Add(v[0], new(big.Int).Mul(v[1], g.Lambda))
+		assert.Equal(t, 0, new(big.Int).Mod(a, bn128.R).Sign())
+	}
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(7)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, g.Lambda), bn128.G2.endomorphism(p)))
+
+	scalars := append(testScalars(t, bn128), big.NewInt(int64(0)), big.NewInt(int64(1)), new(big.Int).Sub(bn128.R, big.NewInt(int64(1))))
+	for _, e := range scalars {
+		k1, k2 := g.decompose(e)
+		assert.True(t, k1.BitLen() <= 128)
+		assert.True(t, k2.BitLen() <= 128)
+
+		expected := bn128.G2.MulScalar(p, new(big.Int).Mod(e, bn128.R))
+		assert.True(t, bn128.G2.Equal(expected, bn128.G2.MulScalarGLS(p, e)))
+	}
+
+	for _, c := range []*big.Int{big.NewInt(int64(1)), new(big.Int).Sub(bn128.R, big.NewInt(int64(1))), g.Lambda} {
+		p2 := bn128.G2.MulScalar(p, c)
+		for _, k := range testJointScalars() {
+			k1, k2 := big.NewInt(k[0]), big.NewInt(k[1])
+			e := new(big.Int).Add(k1, new(big.Int).Mul(k2, c))
+			expected := bn128.G2.MulScalar(p, e.Mod(e, bn128.R))
+			assert.True(t, bn128.G2.Equal(expected, jointMulScalar[[3][2]*big.Int](bn128.G2, bn128.G2.Zero(), p, p2, k1, k2)), "%s %d %d", c, k[0], k[1])
+		}
+	}
+}
+
+func TestG2Table(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	table := bn128.G2.NewTable(bn128.G2.G)
+	scalars := append(testScalars(t, bn128), tableEdgeScalars(bn128)...)
+	for _, e := range scalars {
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(bn128.G2.G, e), table.MulScalar(e)), e.String())
+	}
+	assert.True(t, bn128.G2.IsZero(table.MulScalar(big.NewInt(int64(0)))))
+	assert.True(t, bn128.G2.IsZero(table.MulScalar(bn128.R)))
+
+	ops := tableOps[[3][2]*big.Int](bn128.G2, bn128.G2.IsZero, table.table, scalars)
+	for i := range ops {
+		assert.Equal(t, ops[0], ops[i])
+	}
+}
+
+func TestG2AddComplete(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(7)))
+	q := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(9)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.Add(p, q), bn128.G2.addComplete(p, q)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.Double(p), bn128.G2.addComplete(p, p)))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.addComplete(p, bn128.G2.Neg(p))))
+	assert.True(t, bn128.G2.Equal(p, bn128.G2.addComplete(p, bn128.G2.Zero())))
+	assert.True(t, bn128.G2.Equal(p, bn128.G2.addComplete(bn128.G2.Zero(), p)))
+}
+
+func TestG2Points(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	jac := G2JacFromArray(p)
+	affine := jac.Affine()
+	a := bn128.G2.Affine(p)
+	assert.Equal(t, [2][2]*big.Int{a[0], a[1]}, [2][2]*big.Int{affine.X, affine.Y})
+	assert.True(t, affine.Jac().Equal(jac))
+	assert.True(t, G2AffineFromArray(affine.Array()).Equal(affine))
+	assert.True(t, affine.IsOnCurve())
+	assert.True(t, affine.IsInSubgroup())
+	assert.True(t, jac.IsInSubgroup())
+	assert.False(t, G2Affine{affine.X, affine.X}.IsOnCurve())
+
+	// a point of the twist curve which is not in the subgroup of order r
+	x := [2]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(1))}
+	for {
+		y, ok := bn128.Fq2.Sqrt(bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.TwistCoefB))
+		if ok {
+			q := G2Affine{x, y}
+			assert.True(t, q.IsOnCurve())
+			assert.False(t, q.IsInSubgroup())
+			break
+		}
+		x = bn128.Fq2.Add(x, bn128.Fq2.One())
+	}
+
+	inf := G2JacFromArray(bn128.G2.Zero())
+	assert.True(t, inf.IsInfinity())
+	assert.True(t, inf.Affine().IsInfinity())
+	assert.True(t, G2Affine{}.Equal(inf.Affine()))
+	assert.True(t, bn128.G2.IsZero(G2Affine{}.Array()))
+	assert.Equal(t, "infinity", G2Affine{}.String())
+
+	affines := G2AffinesFromArrays([][3][2]*big.Int{p, inf.Array()})
+	assert.True(t, affines[0].Equal(affine))
+	assert.True(t, affines[1].IsInfinity())
+
+	b, err := json.Marshal(affine)
+	assert.Nil(t, err)
+	var decoded G2Jac
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.True(t, decoded.Equal(jac))
+}
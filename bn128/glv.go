@@ -0,0 +1,124 @@
+package bn128
+
+import (
+	"math/big"
+)
+
+// The GLV method (https://www.iacr.org/archive/crypto2001/21390189.pdf) uses an
+// endomorphism of the curve which acts on the subgroup of order R as the
+// multiplication by Lambda, to split the scalar k into k1 + k2*Lambda mod R,
+// with k1 and k2 of half the size of R, and then compute k1*P + k2*phi(P) with
+// a joint double-and-add of half the length. For G1 phi(x, y) = (beta*x, y),
+// with beta a cube root of unity in Fq, and for G2 (GLS) phi is the
+// untwist-Frobenius-twist endomorphism, which acts as the multiplication by
+// Q mod R.
+
+// glv are the parameters of the decomposition of the scalars, with v1 and v2 a
+// reduced basis of the lattice {(a, b) : a + b*Lambda = 0 mod R}
+type glv struct {
+	Lambda *big.Int
+	R      *big.Int
+	v1     [2]*big.Int
+	v2     [2]*big.Int
+	det    *big.Int // v1[0]*v2[1] - v1[1]*v2[0], which is R or -R
+}
+
+// newGLV returns the parameters of the decomposition for lambda, computing the
+// basis with the extended Euclidean algorithm of R and lambda (section 4 of
+// the GLV paper)
+func newGLV(lambda, r *big.Int) glv {
+	sqrtR := new(big.Int).Sqrt(r)
+
+	// remainders r_i and coefficients t_i, with s_i*R + t_i*lambda = r_i
+	r0, r1 := new(big.Int).Set(r), new(big.Int).Set(lambda)
+	t0, t1 := big.NewInt(int64(0)), big.NewInt(int64(1))
+	for r1.Cmp(sqrtR) >= 0 {
+		q := new(big.Int).Div(r0, r1)
+		r0, r1 = r1, new(big.Int).Sub(r0, new(big.Int).Mul(q, r1))
+		t0, t1 = t1, new(big.Int).Sub(t0, new(big.Int).Mul(q, t1))
+	}
+	// r1 is the first remainder lower than sqrt(R)
+	q := new(big.Int).Div(r0, r1)
+	r2 := new(big.Int).Sub(r0, new(big.Int).Mul(q, r1))
+	t2 := new(big.Int).Sub(t0, new(big.Int).Mul(q, t1))
+
+	g := glv{Lambda: lambda, R: r}
+	g.v1 = [2]*big.Int{r1, new(big.Int).Neg(t1)}
+	g.v2 = [2]*big.Int{r0, new(big.Int).Neg(t0)}
+	if norm(r2, t2).Cmp(norm(r0, t0)) < 0 {
+		g.v2 = [2]*big.Int{r2, new(big.Int).Neg(t2)}
+	}
+	g.det = new(big.Int).Sub(
+		new(big.Int).Mul(g.v1[0], g.v2[1]),
+		new(big.Int).Mul(g.v1[1], g.v2[0]))
+	return g
+}
+
+func norm(a, b *big.Int) *big.Int {
+	return new(big.Int).Add(new(big.Int).Mul(a, a), new(big.Int).Mul(b, b))
+}
+
+// roundDiv returns the integer nearest to a/b
+func roundDiv(a, b *big.Int) *big.Int {
+	if b.Sign() < 0 {
+		a, b = new(big.Int).Neg(a), new(big.Int).Neg(b)
+	}
+	// floor((2a + b) / 2b)
+	n := new(big.Int).Add(new(big.Int).Lsh(a, 1), b)
+	return n.Div(n, new(big.Int).Lsh(b, 1))
+}
+
+// decompose returns k1, k2 with k = k1 + k2*Lambda mod R, of about half the
+// bits of R, and which can be negative
+func (g glv) decompose(k *big.Int) (*big.Int, *big.Int) {
+	k = new(big.Int).Mod(k, g.R)
+	// (k, 0) = c1*v1 + c2*v2, with c1 = k*v2[1]/det and c2 = -k*v1[1]/det
+	c1 := roundDiv(new(big.Int).Mul(k, g.v2[1]), g.det)
+	c2 := roundDiv(new(big.Int).Neg(new(big.Int).Mul(k, g.v1[1])), g.det)
+
+	k1 := new(big.Int).Sub(k, new(big.Int).Mul(c1, g.v1[0]))
+	k1.Sub(k1, new(big.Int).Mul(c2, g.v2[0]))
+	k2 := new(big.Int).Neg(new(big.Int).Mul(c1, g.v1[1]))
+	k2.Sub(k2, new(big.Int).Mul(c2, g.v2[1]))
+	return k1, k2
+}
+
+// jointGroup are the point operations used by jointMulScalar
+type jointGroup[P any] interface {
+	addComplete(p1, p2 P) P
+	Double(p P) P
+	Neg(p P) P
+}
+
+// jointMulScalar returns k1*p1 + k2*p2 with a joint double-and-add (Shamir's
+// trick), doing one Double for each bit of the largest scalar, and one
+// addition where any of the bits is set. The additions are complete, as the
+// accumulated point can be p1, p2, p1 + p2 or their negatives (like for
+// p1 = p2, or small scalars)
+func jointMulScalar[P any](g jointGroup[P], zero, p1, p2 P, k1, k2 *big.Int) P {
+	if k1.Sign() < 0 {
+		p1, k1 = g.Neg(p1), new(big.Int).Neg(k1)
+	}
+	if k2.Sign() < 0 {
+		p2, k2 = g.Neg(p2), new(big.Int).Neg(k2)
+	}
+	p12 := g.addComplete(p1, p2)
+
+	q := zero
+	n := k1.BitLen()
+	if k2.BitLen() > n {
+		n = k2.BitLen()
+	}
+	for i := n - 1; i >= 0; i-- {
+		q = g.Double(q)
+		b1, b2 := k1.Bit(i), k2.Bit(i)
+		if b1 == 1 && b2 == 1 {
+			q = g.addComplete(q, p12)
+		} else if b1 == 1 {
+			q = g.addComplete(q, p1)
+		} else if b2 == 1 {
+			q = g.addComplete(q, p2)
+		}
+	}
+	return q
+}
@@ -0,0 +1,51 @@
+package bn128
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// ladderGroup are the point operations used by the Montgomery ladder
+type ladderGroup[P any] interface {
+	Add(p1, p2 P) P
+	Double(p P) P
+	cswap(p1, p2 P, b uint) (P, P)
+}
+
+// montgomeryLadder returns p*e for a point p of the subgroup of the given
+// order. The scalar is replaced by (e mod order) + order, or + 2*order when
+// that is lower than 2^n (n the bit length of the order), so it has always
+// n+1 bits with the top one set. Then the ladder starts at (p, 2p) and does
+// one Add, one Double and two conditional swaps for each of the remaining n
+// bits, whatever the value of e. The intermediate points are not the infinity
+// point (except for e = -2, -1, 0, 1 mod order), so Add and Double do not take
+// their early returns.
+// The operations are over big.Int, which is not constant time itself, but the
+// sequence of operations does not depend on the bits of the scalar
+func montgomeryLadder[P any](g ladderGroup[P], p P, e, order *big.Int) P {
+	n := order.BitLen()
+	k := new(big.Int).Mod(e, order)
+	k.Add(k, order)
+	// add the order again if the bit n is not set, without branching
+	notSet := big.NewInt(int64(1 - k.Bit(n)))
+	k.Add(k, notSet.Mul(notSet, order))
+
+	r0 := p
+	r1 := g.Double(p)
+	for i := n - 1; i >= 0; i-- {
+		b := k.Bit(i)
+		r0, r1 = g.cswap(r0, r1, b)
+		r1 = g.Add(r0, r1)
+		r0 = g.Double(r0)
+		r0, r1 = g.cswap(r0, r1, b)
+	}
+	return r0
+}
+
+// cswapInt returns (a, b) for bit 0 and (b, a) for bit 1, as a + bit*(b-a) and
+// b - bit*(b-a) over the field
+func cswapInt(f fields.Fq, a, b *big.Int, bit *big.Int) (*big.Int, *big.Int) {
+	d := f.Mul(bit, f.Sub(b, a))
+	return f.Add(a, d), f.Sub(b, d)
+}
@@ -0,0 +1,378 @@
+package bn128
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// The point types give the coordinate system of the points, which in the rest
+// of the package are arrays in Jacobian form ([3]*big.Int for G1 and
+// [3][2]*big.Int for G2, where x = X/Z^2 and y = Y/Z^3). The affine infinity
+// point is (0, 0), as in the byte encoding, and the zero value of the types is
+// the infinity point. The JSON form of the points is the hex string of their
+// compressed encoding, and decoding it checks that they are on the curve and
+// in the subgroup of order R
+
+// G1Affine is a G1 point in affine coordinates
+type G1Affine struct {
+	X, Y *big.Int
+}
+
+// G1Jac is a G1 point in Jacobian coordinates
+type G1Jac struct {
+	X, Y, Z *big.Int
+}
+
+// G2Affine is a G2 point in affine coordinates
+type G2Affine struct {
+	X, Y [2]*big.Int
+}
+
+// G2Jac is a G2 point in Jacobian coordinates
+type G2Jac struct {
+	X, Y, Z [2]*big.Int
+}
+
+// curve is the BN128 used by the methods of the point types
+var curve = func() Bn128 {
+	b, err := NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}()
+
+func isZeroInt(a *big.Int) bool {
+	return a == nil || a.Sign() == 0
+}
+
+func intOrZero(a *big.Int) *big.Int {
+	if a == nil {
+		return big.NewInt(int64(0))
+	}
+	return a
+}
+
+// G1JacFromArray returns the G1Jac of the point in the array form
+func G1JacFromArray(p [3]*big.Int) G1Jac {
+	return G1Jac{p[0], p[1], p[2]}
+}
+
+// G1AffineFromArray returns the G1Affine of the point in the array form
+func G1AffineFromArray(p [3]*big.Int) G1Affine {
+	return G1JacFromArray(p).Affine()
+}
+
+// G1AffinesFromArrays returns the G1Affine of the points in the array form,
+// with only one inversion
+func G1AffinesFromArrays(ps [][3]*big.Int) []G1Affine {
+	var r []G1Affine
+	for _, p := range curve.G1.BatchAffine(ps) {
+		if curve.G1.IsZero(p) {
+			r = append(r, G1Affine{})
+			continue
+		}
+		r = append(r, G1Affine{p[0], p[1]})
+	}
+	return r
+}
+
+// G1AffinesToArrays returns the points in the array form
+func G1AffinesToArrays(ps []G1Affine) [][3]*big.Int {
+	var r [][3]*big.Int
+	for _, p := range ps {
+		r = append(r, p.Array())
+	}
+	return r
+}
+
+// Array returns the point in the array form, with z = 1
+func (p G1Affine) Array() [3]*big.Int {
+	return p.Jac().Array()
+}
+
+// Jac returns the point in Jacobian coordinates, with Z = 1
+func (p G1Affine) Jac() G1Jac {
+	if p.IsInfinity() {
+		return G1Jac{curve.Fq1.Zero(), curve.Fq1.One(), curve.Fq1.Zero()}
+	}
+	return G1Jac{p.X, p.Y, curve.Fq1.One()}
+}
+
+// IsInfinity returns if the point is the infinity point
+func (p G1Affine) IsInfinity() bool {
+	return isZeroInt(p.X) && isZeroInt(p.Y)
+}
+
+// IsOnCurve checks y^2 = x^3 + 3
+func (p G1Affine) IsOnCurve() bool {
+	if p.IsInfinity() {
+		return true
+	}
+	return curve.g1IsOnCurve(p.Array())
+}
+
+// IsInSubgroup checks that the point is in the subgroup of order R, which for
+// G1 is the whole curve
+func (p G1Affine) IsInSubgroup() bool {
+	return p.IsOnCurve()
+}
+
+// Equal returns if the points are the same
+func (p G1Affine) Equal(q G1Affine) bool {
+	return curve.G1.Equal(p.Array(), q.Array())
+}
+
+func (p G1Affine) String() string {
+	if p.IsInfinity() {
+		return "infinity"
+	}
+	return fmt.Sprintf("(%s, %s)", p.X, p.Y)
+}
+
+// MarshalJSON encodes the point as the hex string of its compressed encoding
+func (p G1Affine) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(curve.G1ToCompressed(p.Array())))
+}
+
+// UnmarshalJSON decodes the hex string of the compressed encoding, returning
+// an error if the point is not on the curve
+func (p *G1Affine) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	a, err := curve.G1FromCompressed(b)
+	if err != nil {
+		return err
+	}
+	*p = G1AffineFromArray(a)
+	return nil
+}
+
+// Array returns the point in the array form
+func (p G1Jac) Array() [3]*big.Int {
+	return [3]*big.Int{intOrZero(p.X), intOrZero(p.Y), intOrZero(p.Z)}
+}
+
+// Affine returns the point in affine coordinates
+func (p G1Jac) Affine() G1Affine {
+	if p.IsInfinity() {
+		return G1Affine{curve.Fq1.Zero(), curve.Fq1.Zero()}
+	}
+	a := curve.G1.Affine(p.Array())
+	return G1Affine{a[0], a[1]}
+}
+
+// IsInfinity returns if the point is the infinity point (Z = 0)
+func (p G1Jac) IsInfinity() bool {
+	return isZeroInt(p.Z)
+}
+
+// IsOnCurve checks that the point is on the curve
+func (p G1Jac) IsOnCurve() bool {
+	return p.Affine().IsOnCurve()
+}
+
+// IsInSubgroup checks that the point is in the subgroup of order R
+func (p G1Jac) IsInSubgroup() bool {
+	return p.Affine().IsInSubgroup()
+}
+
+// Equal returns if the points are the same, with any Z
+func (p G1Jac) Equal(q G1Jac) bool {
+	return curve.G1.Equal(p.Array(), q.Array())
+}
+
+func (p G1Jac) String() string {
+	if p.IsInfinity() {
+		return "infinity"
+	}
+	return fmt.Sprintf("(%s : %s : %s)", p.X, p.Y, p.Z)
+}
+
+// MarshalJSON encodes the point as the hex string of its compressed encoding
+func (p G1Jac) MarshalJSON() ([]byte, error) {
+	return p.Affine().MarshalJSON()
+}
+
+// UnmarshalJSON decodes the hex string of the compressed encoding, returning
+// an error if the point is not valid
+func (p *G1Jac) UnmarshalJSON(data []byte) error {
+	var a G1Affine
+	if err := a.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*p = a.Jac()
+	return nil
+}
+
+// G2JacFromArray returns the G2Jac of the point in the array form
+func G2JacFromArray(p [3][2]*big.Int) G2Jac {
+	return G2Jac{p[0], p[1], p[2]}
+}
+
+// G2AffineFromArray returns the G2Affine of the point in the array form
+func G2AffineFromArray(p [3][2]*big.Int) G2Affine {
+	return G2JacFromArray(p).Affine()
+}
+
+// G2AffinesFromArrays returns the G2Affine of the points in the array form,
+// with only one inversion
+func G2AffinesFromArrays(ps [][3][2]*big.Int) []G2Affine {
+	var r []G2Affine
+	for _, p := range curve.G2.BatchAffine(ps) {
+		if curve.G2.IsZero(p) {
+			r = append(r, G2Affine{})
+			continue
+		}
+		r = append(r, G2Affine{p[0], p[1]})
+	}
+	return r
+}
+
+// G2AffinesToArrays returns the points in the array form
+func G2AffinesToArrays(ps []G2Affine) [][3][2]*big.Int {
+	var r [][3][2]*big.Int
+	for _, p := range ps {
+		r = append(r, p.Array())
+	}
+	return r
+}
+
+// Array returns the point in the array form, with z = 1
+func (p G2Affine) Array() [3][2]*big.Int {
+	return p.Jac().Array()
+}
+
+// Jac returns the point in Jacobian coordinates, with Z = 1
+func (p G2Affine) Jac() G2Jac {
+	if p.IsInfinity() {
+		z := curve.G2.Zero()
+		return G2Jac{z[0], z[1], z[2]}
+	}
+	return G2Jac{p.X, p.Y, curve.Fq2.One()}
+}
+
+// IsInfinity returns if the point is the infinity point
+func (p G2Affine) IsInfinity() bool {
+	return isZeroInt(p.X[0]) && isZeroInt(p.X[1]) && isZeroInt(p.Y[0]) && isZeroInt(p.Y[1])
+}
+
+// IsOnCurve checks y^2 = x^3 + 3/(9+u)
+func (p G2Affine) IsOnCurve() bool {
+	if p.IsInfinity() {
+		return true
+	}
+	return curve.g2IsOnCurve(p.Array())
+}
+
+// IsInSubgroup checks that the point is on the curve and in the subgroup of
+// order R
+func (p G2Affine) IsInSubgroup() bool {
+	return p.IsOnCurve() && curve.G2.IsZero(curve.G2.MulScalar(p.Array(), curve.R))
+}
+
+// Equal returns if the points are the same
+func (p G2Affine) Equal(q G2Affine) bool {
+	return curve.G2.Equal(p.Array(), q.Array())
+}
+
+func (p G2Affine) String() string {
+	if p.IsInfinity() {
+		return "infinity"
+	}
+	return fmt.Sprintf("((%s, %s), (%s, %s))", p.X[0], p.X[1], p.Y[0], p.Y[1])
+}
+
+// MarshalJSON encodes the point as the hex string of its compressed encoding
+func (p G2Affine) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(curve.G2ToCompressed(p.Array())))
+}
+
+// UnmarshalJSON decodes the hex string of the compressed encoding, returning
+// an error if the point is not on the curve or not in the subgroup of order R
+func (p *G2Affine) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	a, err := curve.G2FromCompressed(b)
+	if err != nil {
+		return err
+	}
+	*p = G2AffineFromArray(a)
+	return nil
+}
+
+// Array returns the point in the array form
+func (p G2Jac) Array() [3][2]*big.Int {
+	var a [3][2]*big.Int
+	for i, c := range [3][2]*big.Int{p.X, p.Y, p.Z} {
+		a[i] = [2]*big.Int{intOrZero(c[0]), intOrZero(c[1])}
+	}
+	return a
+}
+
+// Affine returns the point in affine coordinates
+func (p G2Jac) Affine() G2Affine {
+	if p.IsInfinity() {
+		return G2Affine{curve.Fq2.Zero(), curve.Fq2.Zero()}
+	}
+	a := curve.G2.Affine(p.Array())
+	return G2Affine{a[0], a[1]}
+}
+
+// IsInfinity returns if the point is the infinity point (Z = 0)
+func (p G2Jac) IsInfinity() bool {
+	return isZeroInt(p.Z[0]) && isZeroInt(p.Z[1])
+}
+
+// IsOnCurve checks that the point is on the curve
+func (p G2Jac) IsOnCurve() bool {
+	return p.Affine().IsOnCurve()
+}
+
+// IsInSubgroup checks that the point is on the curve and in the subgroup of
+// order R
+func (p G2Jac) IsInSubgroup() bool {
+	return p.Affine().IsInSubgroup()
+}
+
+// Equal returns if the points are the same, with any Z
+func (p G2Jac) Equal(q G2Jac) bool {
+	return curve.G2.Equal(p.Array(), q.Array())
+}
+
+func (p G2Jac) String() string {
+	if p.IsInfinity() {
+		return "infinity"
+	}
+	return fmt.Sprintf("((%s, %s) : (%s, %s) : (%s, %s))", p.X[0], p.X[1], p.Y[0], p.Y[1], p.Z[0], p.Z[1])
+}
+
+// MarshalJSON encodes the point as the hex string of its compressed encoding
+func (p G2Jac) MarshalJSON() ([]byte, error) {
+	return p.Affine().MarshalJSON()
+}
+
+// UnmarshalJSON decodes the hex string of the compressed encoding, returning
+// an error if the point is not valid
+func (p *G2Jac) UnmarshalJSON(data []byte) error {
+	var a G2Affine
+	if err := a.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*p = a.Jac()
+	return nil
+}
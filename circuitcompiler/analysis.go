@@ -0,0 +1,93 @@
+package circuitcompiler
+
+// reads returns the signals read by the constraint
+func (c Constraint) reads() []string {
+	var signals []string
+	vs := []string{c.V1, c.V2}
+	if c.Assert {
+		vs = append(vs, c.Out)
+	}
+	if c.Op != "in" {
+		vs = append(vs, c.Inputs...)
+	}
+	for _, v := range vs {
+		if isSignal(v) {
+			signals = append(signals, v)
+		}
+	}
+	for _, lc := range []LinearCombination{c.Lin1, c.Lin2, c.LinOut} {
+		for _, term := range lc {
+			if term.Signal != "one" {
+				signals = append(signals, term.Signal)
+			}
+		}
+	}
+	return signals
+}
+
+// liveSignals returns the signals that the public outputs and the assertions
+// depend on
+func (circ *Circuit) liveSignals() map[string]bool {
+	live := map[string]bool{"out": true}
+	for _, c := range circ.Constraints {
+		if c.Assert {
+			for _, v := range c.reads() {
+				live[v] = true
+			}
+		}
+	}
+	// the signals are assigned before being used, so one backwards pass
+	// reaches all their dependencies
+	for i := len(circ.Constraints) - 1; i >= 0; i-- {
+		c := circ.Constraints[i]
+		if !c.Assert && live[c.Out] {
+			for _, v := range c.reads() {
+				live[v] = true
+			}
+		}
+	}
+	return live
+}
+
+// eliminateDead removes the constraints that assign signals that the public
+// outputs and the assertions do not depend on. The constraints using signals
+// before they are set are kept, so their errors are reported
+func (circ *Circuit) eliminateDead() {
+	live := circ.liveSignals()
+	set := make(map[string]bool)
+	var constraints []Constraint
+	for _, c := range circ.Constraints {
+		keep := c.Assert || c.Op == "in" || live[c.Out]
+		for _, v := range c.reads() {
+			if !set[v] {
+				keep = true
+			}
+		}
+		if !c.Assert {
+			set[c.Out] = true
+		}
+		if keep {
+			constraints = append(constraints, c)
+		}
+	}
+	circ.Constraints = constraints
+}
+
+// checkInputs warns about the private inputs that are not constrained by the
+// R1CS, as any value of them is accepted by the verifier
+func (circ *Circuit) checkInputs() {
+	constrained := make(map[string]bool)
+	for _, c := range circ.Constraints {
+		if c.Op == "in" || c.Hint {
+			continue
+		}
+		for _, v := range c.reads() {
+			constrained[v] = true
+		}
+	}
+	for _, c := range circ.Constraints {
+		if c.Op == "in" && !constrained[c.Out] && !existInArray(circ.PublicSignals, c.Out) {
+			circ.Warnings.Add(c.Pos, c.Out, "unconstrained private input")
+		}
+	}
+}
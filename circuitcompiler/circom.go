@@ -0,0 +1,380 @@
+package circuitcompiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+)
+
+// sections of the iden3 binary formats used by circom and snarkjs
+const (
+	r1csHeaderSection      = 1
+	r1csConstraintsSection = 2
+	r1csWire2LabelSection  = 3
+
+	wtnsHeaderSection  = 1
+	wtnsWitnessSection = 2
+)
+
+// binReader reads the little endian values of the iden3 binary formats
+type binReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binReader) next(n int) []byte {
+	if r.err == nil && (n < 0 || n > len(r.data)) {
+		r.err = errors.New("unexpected end of file")
+	}
+	if r.err != nil {
+		// zero values until the error is checked
+		return make([]byte, 8)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+func (r *binReader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.next(4))
+}
+func (r *binReader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.next(8))
+}
+
+// fits returns if n items of size bytes fit in the rest of the data, to check
+// the counts of the files before allocating for them
+func (r *binReader) fits(n, size int) bool {
+	return n >= 0 && size > 0 && n <= len(r.data)/size
+}
+
+// bigInt reads a little endian integer of n8 bytes
+func (r *binReader) bigInt(n8 int) *big.Int {
+	b := r.next(n8)
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// readSections checks the magic and version of the file, and returns the
+// content of its sections by type
+func readSections(r io.Reader, magic string, maxVersion uint32) (map[uint32]*binReader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	br := &binReader{data: data}
+	if string(br.next(4)) != magic {
+		return nil, errors.New("not a " + magic + " file")
+	}
+	if version := br.uint32(); version == 0 || version > maxVersion {
+		return nil, errors.New("unsupported " + magic + " version " + strconv.Itoa(int(version)))
+	}
+	nSections := br.uint32()
+	sections := make(map[uint32]*binReader)
+	for i := 0; i < int(nSections); i++ {
+		sType := br.uint32()
+		size := br.uint64()
+		if size > uint64(len(br.data)) {
+			return nil, errors.New("unexpected end of file")
+		}
+		sections[sType] = &binReader{data: br.next(int(size))}
+	}
+	return sections, br.err
+}
+
+// readPrime reads the field size and prime of a header section, which must be
+// the BN128 scalar field
+func readPrime(header *binReader) (int, error) {
+	n8 := int(header.uint32())
+	prime := header.bigInt(n8)
+	if header.err != nil {
+		return 0, header.err
+	}
+	if prime.Cmp(fqR.Q) != 0 {
+		return 0, errors.New("unsupported prime " + prime.String())
+	}
+	return n8, nil
+}
+
+// ReadR1CS reads a circom .r1cs binary file into a Circuit, also returning the
+// label of each wire (to find its name in the circom .sym file). The circom
+// wires are the signals, named w1, w2, ..., being the first ones the public
+// outputs and inputs, and then the private inputs. As the file only has the
+// constraints, each R1CS constraint is an assertion, and the wires that are
+// not inputs are set by "wire" constraints, whose values are computed by the
+// circom witness generator. So the witness of the Circuit is not given by
+// CalculateWitness, but it is the values of all the wires read by ReadWtns
+func ReadR1CS(r io.Reader) (*Circuit, []uint64, error) {
+	sections, err := readSections(r, "r1cs", 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	header, ok := sections[r1csHeaderSection]
+	if !ok {
+		return nil, nil, errors.New("r1cs header section not found")
+	}
+	n8, err := readPrime(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	nWires := int(header.uint32())
+	nPubOut := int(header.uint32())
+	nPubIn := int(header.uint32())
+	nPrvIn := int(header.uint32())
+	header.uint64() // labels
+	nConstraints := int(header.uint32())
+	if header.err != nil {
+		return nil, nil, header.err
+	}
+	if nWires < 1 || 1+nPubOut+nPubIn+nPrvIn > nWires {
+		return nil, nil, errors.New("invalid number of wires in the r1cs header")
+	}
+	cs, ok := sections[r1csConstraintsSection]
+	if !ok {
+		return nil, nil, errors.New("r1cs constraints section not found")
+	}
+	wire2Label, ok := sections[r1csWire2LabelSection]
+	if !ok {
+		return nil, nil, errors.New("r1cs wire2label section not found")
+	}
+	// each wire has a label of 8 bytes, and each constraint has at least the
+	// three numbers of factors of its linear combinations
+	if !wire2Label.fits(nWires, 8) {
+		return nil, nil, errors.New("r1cs wire2label section too short for " + strconv.Itoa(nWires) + " wires")
+	}
+	if !cs.fits(nConstraints, 3*4) {
+		return nil, nil, errors.New("r1cs constraints section too short for " + strconv.Itoa(nConstraints) + " constraints")
+	}
+
+	circuit := &Circuit{}
+	circuit.Signals = append(circuit.Signals, "one")
+	for i := 1; i < nWires; i++ {
+		name := "w" + strconv.Itoa(i)
+		circuit.Signals = append(circuit.Signals, name)
+		if i > nPubOut && i <= nPubOut+nPubIn+nPrvIn {
+			circuit.Inputs = append(circuit.Inputs, name)
+			circuit.Constraints = append(circuit.Constraints, Constraint{Op: "in", Out: name})
+		} else {
+			circuit.Constraints = append(circuit.Constraints, Constraint{Op: "wire", Out: name})
+		}
+	}
+	circuit.NPublic = nPubOut + nPubIn
+	circuit.PublicSignals = circuit.Signals[1 : circuit.NPublic+1]
+	circuit.NVars = len(circuit.Signals)
+	circuit.NSignals = len(circuit.Signals)
+
+	readLinearCombination := func() LinearCombination {
+		lc := LinearCombination{}
+		nFactors := int(cs.uint32())
+		if cs.err == nil && !cs.fits(nFactors, 4+n8) {
+			cs.err = errors.New("r1cs constraints section too short for " + strconv.Itoa(nFactors) + " factors")
+		}
+		for i := 0; i < nFactors && cs.err == nil; i++ {
+			wire := int(cs.uint32())
+			coeff := cs.bigInt(n8)
+			if wire >= nWires {
+				cs.err = errors.New("wire " + strconv.Itoa(wire) + " out of range")
+				break
+			}
+			lc = lc.add(circuit.Signals[wire], coeff)
+		}
+		return lc
+	}
+	for i := 0; i < nConstraints; i++ {
+		a := readLinearCombination()
+		b := readLinearCombination()
+		c := readLinearCombination()
+		if cs.err != nil {
+			return nil, nil, cs.err
+		}
+		circuit.Constraints = append(circuit.Constraints, Constraint{
+			Op:      "*",
+			Lin1:    a,
+			Lin2:    b,
+			LinOut:  c,
+			Literal: "(" + a.String() + ")*(" + b.String() + ")===" + c.String(),
+			Assert:  true,
+		})
+	}
+
+	var labels []uint64
+	for i := 0; i < nWires; i++ {
+		labels = append(labels, wire2Label.uint64())
+	}
+	return circuit, labels, nil
+}
+
+// ReadWtns reads a circom .wtns binary file, returning the values of all the
+// wires, being the first one the value one
+func ReadWtns(r io.Reader) ([]*big.Int, error) {
+	sections, err := readSections(r, "wtns", 2)
+	if err != nil {
+		return nil, err
+	}
+	header, ok := sections[wtnsHeaderSection]
+	if !ok {
+		return nil, errors.New("wtns header section not found")
+	}
+	n8, err := readPrime(header)
+	if err != nil {
+		return nil, err
+	}
+	nWitness := int(header.uint32())
+	if header.err != nil {
+		return nil, header.err
+	}
+
+	values, ok := sections[wtnsWitnessSection]
+	if !ok {
+		return nil, errors.New("wtns witness section not found")
+	}
+	if !values.fits(nWitness, n8) {
+		return nil, errors.New("wtns witness section too short for " + strconv.Itoa(nWitness) + " values")
+	}
+	var w []*big.Int
+	for i := 0; i < nWitness && values.err == nil; i++ {
+		w = append(w, values.bigInt(n8))
+	}
+	if values.err != nil {
+		return nil, values.err
+	}
+	return w, nil
+}
+
+// binWriter writes the little endian values of the iden3 binary formats
+type binWriter struct {
+	bytes.Buffer
+}
+
+func (w *binWriter) uint32(v uint32) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+func (w *binWriter) uint64(v uint64) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+
+// bigInt writes a little endian integer of 32 bytes
+func (w *binWriter) bigInt(v *big.Int) {
+	be := v.Bytes()
+	le := make([]byte, 32)
+	for i := range be {
+		le[len(be)-1-i] = be[i]
+	}
+	w.Write(le)
+}
+
+// writeSections writes the file with the given sections, by type
+func writeSections(w io.Writer, magic string, version uint32, sections ...*binWriter) error {
+	var file binWriter
+	file.WriteString(magic)
+	file.uint32(version)
+	file.uint32(uint32(len(sections)))
+	for i, section := range sections {
+		file.uint32(uint32(i + 1))
+		file.uint64(uint64(section.Len()))
+		file.Write(section.Bytes())
+	}
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+func writePrime(header *binWriter) {
+	header.uint32(32)
+	header.bigInt(fqR.Q)
+}
+
+// circomWires returns the index in the signals of each circom wire, which
+// are ordered as one, public signals, private inputs, and the other signals
+func (circ *Circuit) circomWires() []int {
+	wires := []int{0}
+	for _, s := range circ.PublicSignals {
+		wires = append(wires, indexInArray(circ.Signals, s))
+	}
+	for _, in := range circ.Inputs {
+		if !existInArray(circ.PublicSignals, in) {
+			wires = append(wires, indexInArray(circ.Signals, in))
+		}
+	}
+	for i, s := range circ.Signals[1:] {
+		if !existInArray(circ.PublicSignals, s) && !existInArray(circ.Inputs, s) {
+			wires = append(wires, i+1)
+		}
+	}
+	return wires
+}
+
+// WriteR1CS writes the R1CS of the Circuit in the circom .r1cs binary format.
+// The public signals are written as public outputs, and the label of each
+// wire is its index
+func (circ *Circuit) WriteR1CS(w io.Writer) error {
+	a, b, c, err := circ.GenerateR1CS()
+	if err != nil {
+		return err
+	}
+	wires := circ.circomWires()
+	nPublic := len(circ.PublicSignals)
+	nPrivate := 0
+	for _, in := range circ.Inputs {
+		if !existInArray(circ.PublicSignals, in) {
+			nPrivate++
+		}
+	}
+
+	var header binWriter
+	writePrime(&header)
+	header.uint32(uint32(len(wires)))
+	header.uint32(uint32(nPublic))
+	header.uint32(0)
+	header.uint32(uint32(nPrivate))
+	header.uint64(uint64(len(wires)))
+	header.uint32(uint32(len(a)))
+
+	var constraints binWriter
+	for i := range a {
+		for _, row := range [][]*big.Int{a[i], b[i], c[i]} {
+			var nFactors uint32
+			var factors binWriter
+			for wire, s := range wires {
+				if fqR.IsZero(fqR.Affine(row[s])) {
+					continue
+				}
+				nFactors++
+				factors.uint32(uint32(wire))
+				factors.bigInt(fqR.Affine(row[s]))
+			}
+			constraints.uint32(nFactors)
+			constraints.Write(factors.Bytes())
+		}
+	}
+
+	var wire2Label binWriter
+	for wire := range wires {
+		wire2Label.uint64(uint64(wire))
+	}
+	return writeSections(w, "r1cs", 1, &header, &constraints, &wire2Label)
+}
+
+// WriteWtns writes the witness of the Circuit in the circom .wtns binary
+// format, with the wires in the order of WriteR1CS
+func (circ *Circuit) WriteWtns(w io.Writer, witness []*big.Int) error {
+	if len(witness) != len(circ.Signals) {
+		return errors.New("witness length != circuit.Signals")
+	}
+	wires := circ.circomWires()
+
+	var header binWriter
+	writePrime(&header)
+	header.uint32(uint32(len(wires)))
+
+	var values binWriter
+	for _, s := range wires {
+		values.bigInt(fqR.Affine(witness[s]))
+	}
+	return writeSections(w, "wtns", 2, &header, &values)
+}
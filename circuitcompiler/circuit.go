@@ -3,8 +3,9 @@ package circuitcompiler
 import (
 	"errors"
 	"math/big"
-	"strconv"
 
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/arnaucube/go-snark/r1csqap"
 )
 
@@ -18,6 +19,8 @@ type Circuit struct {
 	PublicSignals []string
 	Witness       []*big.Int
 	Constraints   []Constraint
+	SourceNames   map[string]string // renamed signals (SSA) -> name in the circuit code
+	Warnings      ErrorList         // possible bugs found in the circuit, like unconstrained private inputs
 	R1CS          struct {
 		A [][]*big.Int
 		B [][]*big.Int
@@ -34,7 +37,26 @@ type Constraint struct {
 	Out     string
 	Literal string
 
-	Inputs []string // in func declaration case
+	Inputs []string // in func declaration case, the bits in the pack case, and the hint function arguments
+	Hint   bool     // out <-- v1 op v2 (or out <-- op(inputs)), where out is assigned but not constrained
+	Assert bool     // v1 op v2 === out, where out is constrained but not assigned
+
+	// linear combinations replacing V1, V2 (and Out in the assertions) when
+	// the optimizer folds other constraints into this one
+	Lin1, Lin2, LinOut LinearCombination
+
+	Pos Position // position in the circuit code
+}
+
+// fqR is the Finite Field over R, where the witness values live
+var fqR = newFqR()
+
+func newFqR() fields.Fq {
+	fqR, err := bn128.NewFqR()
+	if err != nil {
+		panic(err)
+	}
+	return fqR
 }
 
 func indexInArray(arr []string, e string) int {
@@ -45,85 +67,124 @@ func indexInArray(arr []string, e string) int {
 	}
 	return -1
 }
-func isValue(a string) (bool, int) {
-	v, err := strconv.Atoi(a)
-	if err != nil {
-		return false, 0
+func isValue(a string) (bool, *big.Int) {
+	v, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		return false, nil
 	}
 	return true, v
 }
-func insertVar(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
+func isSignal(a string) bool {
+	isVal, _ := isValue(a)
+	return a != "" && !isVal
+}
+func isOperator(op string) bool {
+	return op == "+" || op == "-" || op == "*" || op == "/"
+}
+
+// checkSignal checks that the signal is already set, returning its index in the signals
+func checkSignal(signals []string, v string, used map[string]bool) (int, *Error) {
+	i := indexInArray(signals, v)
+	if i == -1 {
+		return i, &Error{Token: v, Msg: "unknown signal"}
+	}
+	if !used[v] {
+		return i, &Error{Token: v, Msg: "using variable before it's set"}
+	}
+	return i, nil
+}
+func checkUsed(signals []string, v string, used map[string]bool) *Error {
+	if !isSignal(v) {
+		return nil
+	}
+	_, err := checkSignal(signals, v, used)
+	return err
+}
+func insertVar(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, *Error) {
 	isVal, value := isValue(v)
-	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		arr[0] = new(big.Int).Add(arr[0], value)
 	} else {
-		if !used[v] {
-			panic(errors.New("using variable before it's set"))
+		i, err := checkSignal(signals, v, used)
+		if err != nil {
+			return arr, err
 		}
-		arr[indexInArray(signals, v)] = new(big.Int).Add(arr[indexInArray(signals, v)], big.NewInt(int64(1)))
+		arr[i] = new(big.Int).Add(arr[i], big.NewInt(int64(1)))
 	}
-	return arr, used
+	return arr, nil
 }
-func insertVarNeg(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
+func insertVarNeg(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, *Error) {
 	isVal, value := isValue(v)
-	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		arr[0] = new(big.Int).Sub(arr[0], value)
 	} else {
-		if !used[v] {
-			panic(errors.New("using variable before it's set"))
+		i, err := checkSignal(signals, v, used)
+		if err != nil {
+			return arr, err
 		}
-		arr[indexInArray(signals, v)] = new(big.Int).Add(arr[indexInArray(signals, v)], big.NewInt(int64(-1)))
+		arr[i] = new(big.Int).Add(arr[i], big.NewInt(int64(-1)))
 	}
-	return arr, used
+	return arr, nil
 }
 
-// GenerateR1CS generates the R1CS polynomials from the Circuit
-func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
+// insertLinearCombination adds the linear combination lc, or the value or
+// signal v if lc is nil
+func insertLinearCombination(arr []*big.Int, signals []string, v string, lc LinearCombination, used map[string]bool) ([]*big.Int, *Error) {
+	if lc == nil {
+		return insertVar(arr, signals, v, used)
+	}
+	for _, term := range lc {
+		i := 0
+		if term.Signal != "one" {
+			var err *Error
+			if i, err = checkSignal(signals, term.Signal, used); err != nil {
+				return arr, err
+			}
+		}
+		arr[i] = new(big.Int).Add(arr[i], term.Coeff)
+	}
+	return arr, nil
+}
+
+// insertOut inserts the out of the constraint, which in the assertions must be already set
+func insertOut(arr []*big.Int, signals []string, constraint Constraint, used map[string]bool) ([]*big.Int, *Error) {
+	if constraint.Assert {
+		return insertLinearCombination(arr, signals, constraint.Out, constraint.LinOut, used)
+	}
+	i := indexInArray(signals, constraint.Out)
+	if i == -1 {
+		return arr, &Error{Token: constraint.Out, Msg: "unknown signal"}
+	}
+	arr[i] = new(big.Int).Add(arr[i], big.NewInt(int64(1)))
+	used[constraint.Out] = true
+	return arr, nil
+}
+
+// GenerateR1CS generates the R1CS polynomials from the Circuit. If the
+// circuit has errors, the returned error is an ErrorList with all of them
+func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int, error) {
 	// from flat code to R1CS
 
 	var a [][]*big.Int
 	var b [][]*big.Int
 	var c [][]*big.Int
 
+	var errs ErrorList
 	used := make(map[string]bool)
 	for _, constraint := range circ.Constraints {
-		aConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-		bConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-		cConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-
-		// if existInArray(constraint.Out) {
-		if used[constraint.Out] {
-			panic(errors.New("out variable already used: " + constraint.Out))
-		}
-		used[constraint.Out] = true
-		if constraint.Op == "in" {
-			for i := 0; i < len(constraint.Inputs); i++ {
-				aConstraint[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Add(aConstraint[indexInArray(circ.Signals, constraint.Out)], big.NewInt(int64(1)))
-				aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.Out, used)
-				bConstraint[0] = big.NewInt(int64(1))
+		aConstraint, bConstraint, cConstraint, err := circ.constraintToR1CS(constraint, used)
+		if err != nil {
+			err.Pos = constraint.Pos
+			errs = append(errs, err)
+			if !constraint.Assert {
+				// avoid reporting again the errors of the signals that depend on it
+				used[constraint.Out] = true
 			}
 			continue
-
-		} else if constraint.Op == "+" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
-			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
-			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V2, used)
-			bConstraint[0] = big.NewInt(int64(1))
-		} else if constraint.Op == "-" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
-			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V1, used)
-			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V2, used)
-			bConstraint[0] = big.NewInt(int64(1))
-		} else if constraint.Op == "*" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
-			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
-			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
-		} else if constraint.Op == "/" {
-			cConstraint, used = insertVar(cConstraint, circ.Signals, constraint.V1, used)
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
-			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
+		}
+		if aConstraint == nil {
+			// the constraint does not add any row
+			continue
 		}
 
 		a = append(a, aConstraint)
@@ -131,14 +192,94 @@ func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
 		c = append(c, cConstraint)
 
 	}
-	return a, b, c
+	return a, b, c, errs.Err()
+}
+
+// constraintToR1CS returns the R1CS rows (a, b, c) of the constraint, or nil
+// rows if the constraint does not add any
+func (circ *Circuit) constraintToR1CS(constraint Constraint, used map[string]bool) ([]*big.Int, []*big.Int, []*big.Int, *Error) {
+	aConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
+	bConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
+	cConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
+
+	// if existInArray(constraint.Out) {
+	if !constraint.Assert && used[constraint.Out] {
+		return nil, nil, nil, &Error{Token: constraint.Out, Msg: "out variable already used"}
+	}
+	var err *Error
+	if constraint.Op == "in" || constraint.Op == "wire" {
+		used[constraint.Out] = true
+		return nil, nil, nil, nil
+	} else if constraint.Hint {
+		// witness-only operation, the value must be constrained by other constraints
+		for _, in := range append([]string{constraint.V1, constraint.V2}, constraint.Inputs...) {
+			if err = checkUsed(circ.Signals, in, used); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		used[constraint.Out] = true
+		return nil, nil, nil, nil
+	} else if constraint.Op == "+" {
+		if aConstraint, err = insertVar(aConstraint, circ.Signals, constraint.V1, used); err != nil {
+			return nil, nil, nil, err
+		}
+		if aConstraint, err = insertVar(aConstraint, circ.Signals, constraint.V2, used); err != nil {
+			return nil, nil, nil, err
+		}
+		bConstraint[0] = big.NewInt(int64(1))
+	} else if constraint.Op == "-" {
+		if aConstraint, err = insertVar(aConstraint, circ.Signals, constraint.V1, used); err != nil {
+			return nil, nil, nil, err
+		}
+		if aConstraint, err = insertVarNeg(aConstraint, circ.Signals, constraint.V2, used); err != nil {
+			return nil, nil, nil, err
+		}
+		bConstraint[0] = big.NewInt(int64(1))
+	} else if constraint.Op == "*" {
+		if aConstraint, err = insertLinearCombination(aConstraint, circ.Signals, constraint.V1, constraint.Lin1, used); err != nil {
+			return nil, nil, nil, err
+		}
+		if bConstraint, err = insertLinearCombination(bConstraint, circ.Signals, constraint.V2, constraint.Lin2, used); err != nil {
+			return nil, nil, nil, err
+		}
+	} else if constraint.Op == "/" {
+		// out = v1 / v2 is constrained as out * v2 = v1
+		if cConstraint, err = insertLinearCombination(cConstraint, circ.Signals, constraint.V1, constraint.Lin1, used); err != nil {
+			return nil, nil, nil, err
+		}
+		if bConstraint, err = insertLinearCombination(bConstraint, circ.Signals, constraint.V2, constraint.Lin2, used); err != nil {
+			return nil, nil, nil, err
+		}
+	} else if constraint.Op == "pack" {
+		// sum(bit_i * 2^i)
+		for i, bit := range constraint.Inputs {
+			j, err := checkSignal(circ.Signals, bit, used)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			pow := new(big.Int).Lsh(big.NewInt(int64(1)), uint(i))
+			aConstraint[j] = new(big.Int).Add(aConstraint[j], pow)
+		}
+		bConstraint[0] = big.NewInt(int64(1))
+	} else {
+		return nil, nil, nil, &Error{Token: constraint.Op, Msg: "unknown operation"}
+	}
+
+	if constraint.Op == "/" {
+		aConstraint, err = insertOut(aConstraint, circ.Signals, constraint, used)
+	} else {
+		cConstraint, err = insertOut(cConstraint, circ.Signals, constraint, used)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return aConstraint, bConstraint, cConstraint, nil
 }
 
 func grabVar(signals []string, w []*big.Int, vStr string) *big.Int {
 	isVal, v := isValue(vStr)
-	vBig := big.NewInt(int64(v))
 	if isVal {
-		return vBig
+		return v
 	} else {
 		return w[indexInArray(signals, vStr)]
 	}
@@ -152,19 +293,70 @@ func (circ *Circuit) CalculateWitness(inputs []*big.Int) ([]*big.Int, error) {
 	w := r1csqap.ArrayOfBigZeros(len(circ.Signals))
 	w[0] = big.NewInt(int64(1))
 	for i, input := range inputs {
-		w[i+2] = input
+		w[indexInArray(circ.Signals, circ.Inputs[i])] = input
 	}
 	for _, constraint := range circ.Constraints {
-		if constraint.Op == "in" {
-		} else if constraint.Op == "+" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Add(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "-" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Sub(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "*" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Mul(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "/" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Div(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
+		if constraint.Assert || constraint.Op == "in" {
+			// nothing to calculate, the out value is already set
+			continue
 		}
+		v, err := circ.calculateConstraint(constraint, w)
+		if err != nil {
+			return []*big.Int{}, err
+		}
+		w[indexInArray(circ.Signals, constraint.Out)] = v
 	}
 	return w, nil
 }
+
+// calculateConstraint calculates the out value of the constraint, from the values already in the witness
+func (circ *Circuit) calculateConstraint(constraint Constraint, w []*big.Int) (*big.Int, error) {
+	if constraint.Hint && !isOperator(constraint.Op) {
+		f, err := getHint(constraint.Op)
+		if err != nil {
+			return nil, err
+		}
+		var inputs []*big.Int
+		for _, in := range constraint.Inputs {
+			inputs = append(inputs, grabVar(circ.Signals, w, in))
+		}
+		r, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return fqR.Affine(r), nil
+	}
+	if constraint.Op == "wire" {
+		return nil, errors.New("the value of " + constraint.Out + " is computed outside of the circuit")
+	}
+	if constraint.Op == "pack" {
+		r := big.NewInt(int64(0))
+		for i, bit := range constraint.Inputs {
+			r = fqR.Add(r, new(big.Int).Lsh(grabVar(circ.Signals, w, bit), uint(i)))
+		}
+		return r, nil
+	}
+
+	var v1, v2 *big.Int
+	if constraint.Lin1 != nil {
+		v1 = circ.evalLinearCombination(constraint.Lin1, w)
+		v2 = circ.evalLinearCombination(constraint.Lin2, w)
+	} else {
+		v1 = grabVar(circ.Signals, w, constraint.V1)
+		v2 = grabVar(circ.Signals, w, constraint.V2)
+	}
+	switch constraint.Op {
+	case "+":
+		return fqR.Add(v1, v2), nil
+	case "-":
+		return fqR.Sub(v1, v2), nil
+	case "*":
+		return fqR.Mul(v1, v2), nil
+	case "/":
+		if fqR.IsZero(fqR.Affine(v2)) {
+			return nil, errors.New("division by zero in " + constraint.Literal)
+		}
+		return fqR.Div(v1, fqR.Affine(v2)), nil
+	}
+	return nil, errors.New("unknown operation: " + constraint.Op)
+}
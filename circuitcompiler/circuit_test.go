@@ -1,10 +1,13 @@
 package circuitcompiler
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,7 +41,8 @@ func TestCircuitParser(t *testing.T) {
 
 	// flat code to R1CS
 	fmt.Println("generating R1CS from flat code")
-	a, b, c := circuit.GenerateR1CS()
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
 	fmt.Print("function with inputs: ")
 	fmt.Println(circuit.Inputs)
 
@@ -82,3 +86,715 @@ func TestCircuitParser(t *testing.T) {
 	assert.Nil(t, err)
 	fmt.Println("w", w)
 }
+
+// r1csSatisfied checks that the witness satisfies A·w * B·w == C·w over R for all the constraints
+func r1csSatisfied(a, b, c [][]*big.Int, w []*big.Int) bool {
+	for i := 0; i < len(a); i++ {
+		aw := fqR.Zero()
+		bw := fqR.Zero()
+		cw := fqR.Zero()
+		for j := 0; j < len(w); j++ {
+			aw = fqR.Add(aw, fqR.Mul(a[i][j], w[j]))
+			bw = fqR.Add(bw, fqR.Mul(b[i][j], w[j]))
+			cw = fqR.Add(cw, fqR.Mul(c[i][j], w[j]))
+		}
+		if !fqR.Equal(fqR.Mul(aw, bw), cw) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCircuitSubtraction(t *testing.T) {
+	// the "-" row is v1 - v2, also with a constant v2
+	flat := `
+	func test(x, y):
+		z = x - y
+		out = z - 3
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(10), big.NewInt(4)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(3), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+	// z = x - y and out = z - 3, not -x - y and -z + 3
+	assert.Equal(t, []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(1), big.NewInt(-1), big.NewInt(0)}, a[0])
+	assert.Equal(t, []*big.Int{big.NewInt(-3), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(1)}, a[1])
+}
+
+func TestCircuitWitnessInputs(t *testing.T) {
+	// without out, the inputs are not from w[2], and are set by their name
+	flat := `
+	func test(x, y):
+		z = x * y
+		z === 12
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "x", "y", "z"}, circuit.Signals)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(3), big.NewInt(4)})
+	assert.Nil(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(4), big.NewInt(12)}, w)
+	assert.True(t, r1csSatisfied(a, b, c, w))
+}
+
+func TestCircuitFieldWitness(t *testing.T) {
+	// the witness is computed over R, and the constants can be big integers
+	flat := `
+	func test(x, y):
+		z = x - y
+		s = z * z
+		out = s + 18446744073709551616
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(1), big.NewInt(3)})
+	assert.Nil(t, err)
+	for _, v := range w {
+		assert.True(t, v.Sign() >= 0 && v.Cmp(fqR.Q) < 0, v.String())
+	}
+	// (1 - 3)^2 + 2^64
+	assert.Equal(t, new(big.Int).Add(big.NewInt(4), new(big.Int).Lsh(big.NewInt(1), 64)), fqR.Affine(w[1]))
+	assert.True(t, r1csSatisfied(a, b, c, w))
+}
+
+func TestCircuitComparisons(t *testing.T) {
+	cases := []struct {
+		op       string
+		expected func(x, y int64) bool
+	}{
+		{"<", func(x, y int64) bool { return x < y }},
+		{"<=", func(x, y int64) bool { return x <= y }},
+		{">", func(x, y int64) bool { return x > y }},
+		{">=", func(x, y int64) bool { return x >= y }},
+		{"==", func(x, y int64) bool { return x == y }},
+		{"!=", func(x, y int64) bool { return x != y }},
+	}
+	pairs := [][2]int64{{3, 5}, {5, 3}, {4, 4}, {0, 1}, {1, 0}, {0, 0}}
+
+	for _, cs := range cases {
+		flat := `
+		func test(balance, amount):
+			out = balance ` + cs.op + ` amount
+		`
+		parser := NewParser(strings.NewReader(flat))
+		circuit, err := parser.Parse()
+		assert.Nil(t, err)
+		a, b, c, err := circuit.GenerateR1CS()
+		assert.Nil(t, err)
+
+		for _, pair := range pairs {
+			w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(pair[0]), big.NewInt(pair[1])})
+			assert.Nil(t, err)
+			expected := big.NewInt(0)
+			if cs.expected(pair[0], pair[1]) {
+				expected = big.NewInt(1)
+			}
+			assert.Equal(t, expected, w[1], "%d %s %d", pair[0], cs.op, pair[1])
+			assert.True(t, r1csSatisfied(a, b, c, w), "%d %s %d", pair[0], cs.op, pair[1])
+
+			// a witness claiming the opposite result must not satisfy the R1CS
+			w[1] = new(big.Int).Sub(big.NewInt(1), w[1])
+			assert.False(t, r1csSatisfied(a, b, c, w), "%d %s %d", pair[0], cs.op, pair[1])
+		}
+	}
+}
+
+func TestCircuitComparisonWithValue(t *testing.T) {
+	flat := `
+	func test(x):
+		y = x * x
+		out = y >= 10
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(0), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	w, err = circuit.CalculateWitness([]*big.Int{big.NewInt(4)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+}
+
+func TestCircuitComparisonBits(t *testing.T) {
+	flat := `func test(x, y):
+	out = x < y
+`
+	parser := NewParser(strings.NewReader(flat))
+	parser.ComparisonBits = 8
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(200), big.NewInt(255)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+	// the shifted difference, its 8+1 bits, their packing, and the negation
+	assert.Equal(t, 2+8+1+1+1, len(a))
+
+	// the auxiliary signals can not be named in the code
+	for _, s := range circuit.Signals {
+		if s != "one" && s != "out" && s != "x" && s != "y" {
+			assert.True(t, strings.HasPrefix(s, internalPrefix), s)
+		}
+	}
+	parser = NewParser(strings.NewReader("func test(x):\n\t_out_geq = x + 1\n\tout = x < 1\n"))
+	_, err = parser.Parse()
+	assert.NotNil(t, err)
+
+	for _, bits := range []int{0, -1, maxComparisonBits + 1} {
+		parser = NewParser(strings.NewReader(flat))
+		parser.ComparisonBits = bits
+		_, err = parser.Parse()
+		assert.NotNil(t, err)
+		errs := err.(ErrorList)
+		assert.Equal(t, Position{Line: 2, Column: 2}, errs[0].Pos)
+		assert.Equal(t, "<", errs[0].Token)
+		assert.Equal(t, "the comparison bits must be between 1 and 252", errs[0].Msg)
+	}
+
+	// the equality comparisons do not use the bits
+	parser = NewParser(strings.NewReader("func test(x, y):\n\tout = x == y\n"))
+	parser.ComparisonBits = 0
+	_, err = parser.Parse()
+	assert.Nil(t, err)
+}
+
+func TestCircuitHints(t *testing.T) {
+	flat := `
+	func test(x, y):
+		inv <-- 1/x
+		x * inv === 1
+		out = inv * y
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	// the hint does not add any constraint
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(a))
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(4), big.NewInt(8)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(2), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	_, err = circuit.CalculateWitness([]*big.Int{big.NewInt(0), big.NewInt(8)})
+	assert.NotNil(t, err)
+
+	// integer division with remainder, using the builtin hints
+	flat = `
+	func test(a, b):
+		q <-- idiv(a, b)
+		r <-- mod(a, b)
+		qb = q * b
+		qb + r === a
+		rlt = r < b
+		rlt === 1
+		out = q * 1
+	`
+	parser = NewParser(strings.NewReader(flat))
+	circuit, err = parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err = circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err = circuit.CalculateWitness([]*big.Int{big.NewInt(23), big.NewInt(5)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(4), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+}
+
+func TestCircuitRegisterHint(t *testing.T) {
+	err := RegisterHint("isqrt", func(inputs []*big.Int) (*big.Int, error) {
+		return new(big.Int).Sqrt(inputs[0]), nil
+	})
+	assert.Nil(t, err)
+	// hints can not be registered twice
+	assert.NotNil(t, RegisterHint("isqrt", nil))
+
+	flat := `
+	func test(x):
+		r <-- isqrt(x)
+		r * r === x
+		out = r * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(49)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(7), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	// 50 is not a square, the hint value does not pass the assertion
+	w, err = circuit.CalculateWitness([]*big.Int{big.NewInt(50)})
+	assert.Nil(t, err)
+	assert.False(t, r1csSatisfied(a, b, c, w))
+
+	flat = `
+	func test(x):
+		r <-- notregistered(x)
+		out = r * x
+	`
+	parser = NewParser(strings.NewReader(flat))
+	circuit, err = parser.Parse()
+	assert.Nil(t, err)
+	_, err = circuit.CalculateWitness([]*big.Int{big.NewInt(49)})
+	assert.NotNil(t, err)
+}
+
+func TestCircuitParserErrors(t *testing.T) {
+	flat := `func test(x):
+	aux = x*x
+	y = aux ^ x
+	5 = x * x
+	z = x + y +
+	w = x +
+	out = z + 5
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.NotNil(t, err)
+	errs, ok := err.(ErrorList)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(errs))
+
+	assert.Equal(t, Position{Line: 3, Column: 10}, errs[0].Pos)
+	assert.Equal(t, "^", errs[0].Token)
+	assert.Equal(t, Position{Line: 4, Column: 2}, errs[1].Pos)
+	assert.Equal(t, "5", errs[1].Token)
+	assert.Equal(t, Position{Line: 5, Column: 12}, errs[2].Pos)
+	assert.Equal(t, "+", errs[2].Token)
+	assert.Equal(t, Position{Line: 6, Column: 9}, errs[3].Pos)
+	assert.Equal(t, "\n", errs[3].Token)
+	assert.Equal(t, "3:10: expected an operator (\"^\") (and 3 more errors)", err.Error())
+
+	// the lines without errors are parsed
+	assert.Equal(t, 3, len(circuit.Constraints))
+}
+
+func TestCircuitCompileErrors(t *testing.T) {
+	flat := `func test(x):
+	y = x * z
+	y = x * x
+	out = y + w
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	_, _, _, err = circuit.GenerateR1CS()
+	assert.NotNil(t, err)
+	errs, ok := err.(ErrorList)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(errs))
+
+	assert.Equal(t, Position{Line: 2, Column: 2}, errs[0].Pos)
+	assert.Equal(t, "z", errs[0].Token)
+	assert.Equal(t, "using variable before it's set", errs[0].Msg)
+	assert.Equal(t, Position{Line: 4, Column: 2}, errs[1].Pos)
+	assert.Equal(t, "w", errs[1].Token)
+}
+
+func TestCircuitReassignment(t *testing.T) {
+	flat := `func test(x):
+	acc = x * x
+	acc = acc * x
+	acc = acc * x
+	out = acc + 1
+	out = out * 2
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	fmt.Println(circuit)
+	assert.Equal(t, []string{"one", "out", "x", "acc", "acc.1", "acc.2", "out.1"}, circuit.Signals)
+	assert.Equal(t, "acc.1", circuit.Constraints[3].V1)
+	assert.Equal(t, "acc", circuit.SourceName("acc.2"))
+	assert.Equal(t, "out", circuit.SourceName("out.1"))
+	assert.Equal(t, "x", circuit.SourceName("x"))
+
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(3)})
+	assert.Nil(t, err)
+	// (3^4 + 1) * 2
+	assert.Equal(t, big.NewInt(164), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+}
+
+func TestCircuitCommentsAndImports(t *testing.T) {
+	parser, err := NewFileParser("testdata/main.circuit")
+	assert.Nil(t, err)
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "out", "x", "square", "cube", "z"}, circuit.Signals)
+	assert.Equal(t, "testdata/lib/square.circuit", circuit.Constraints[1].Pos.File)
+	assert.Equal(t, 2, circuit.Constraints[1].Pos.Line)
+
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(35), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	parser, err = NewFileParser("testdata/cycle_a.circuit")
+	assert.Nil(t, err)
+	_, err = parser.Parse()
+	assert.NotNil(t, err)
+	errs := err.(ErrorList)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "import cycle", errs[0].Msg)
+	assert.Equal(t, "testdata/cycle_b.circuit:1:1", errs[0].Pos.String())
+}
+
+func TestCircuitOptimizer(t *testing.T) {
+	flat := `func test(x, y):
+	a = x + y
+	b = a * 3
+	c = b - x
+	d = c * a
+	e = d / 2
+	f = e + 7
+	g = f * x
+	out = g - a
+	lt = x < y
+	z = lt * y
+	z === 0
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+
+	parser = NewParser(strings.NewReader(flat))
+	parser.Optimize = true
+	optCircuit, err := parser.Parse()
+	assert.Nil(t, err)
+	optA, optB, optC, err := optCircuit.GenerateR1CS()
+	assert.Nil(t, err)
+	assert.True(t, len(optA) < len(a))
+	assert.True(t, len(optCircuit.Signals) < len(circuit.Signals))
+	assert.Equal(t, -1, indexInArray(optCircuit.Signals, "c"))
+
+	for _, in := range [][]int64{{5, 3}, {7, 2}, {0, 0}} {
+		inputs := []*big.Int{big.NewInt(in[0]), big.NewInt(in[1])}
+		w, err := circuit.CalculateWitness(inputs)
+		assert.Nil(t, err)
+		optW, err := optCircuit.CalculateWitness(inputs)
+		assert.Nil(t, err)
+		assert.True(t, r1csSatisfied(a, b, c, w))
+		assert.True(t, r1csSatisfied(optA, optB, optC, optW))
+
+		// the witness of the circuit, without the folded signals, is
+		// accepted by the optimized circuit
+		projW := make([]*big.Int, len(optCircuit.Signals))
+		for i, s := range optCircuit.Signals {
+			projW[i] = w[indexInArray(circuit.Signals, s)]
+		}
+		assert.Equal(t, optW, projW)
+
+		// a wrong out is rejected by both
+		w[1] = fqR.Add(w[1], big.NewInt(1))
+		optW[1] = fqR.Add(optW[1], big.NewInt(1))
+		assert.False(t, r1csSatisfied(a, b, c, w))
+		assert.False(t, r1csSatisfied(optA, optB, optC, optW))
+	}
+}
+
+func TestCircuitDeadCode(t *testing.T) {
+	flat := `func test(x, y, k):
+	a = x * x
+	unused = a * x
+	b = unused * 2
+	r <-- inv(k)
+	r * x === 1
+	out = a * x
+`
+	parser := NewParser(strings.NewReader(flat))
+	parser.Optimize = true
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	fmt.Println(circuit)
+	assert.Equal(t, []string{"one", "out", "x", "y", "k", "a", "r"}, circuit.Signals)
+	assert.Equal(t, 7, len(circuit.Constraints))
+
+	// y is not used, and k is only used by the hint
+	assert.Equal(t, 2, len(circuit.Warnings))
+	assert.Equal(t, "y", circuit.Warnings[0].Token)
+	assert.Equal(t, "k", circuit.Warnings[1].Token)
+	assert.Equal(t, "unconstrained private input", circuit.Warnings[1].Msg)
+
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(27), w[1])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	parser = NewParser(strings.NewReader(flat))
+	circuit, err = parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(circuit.Constraints))
+	assert.Equal(t, 2, len(circuit.Warnings))
+}
+
+func TestCircuitStats(t *testing.T) {
+	flat := `
+	func test(x):
+		aux = x*x
+		y = aux*x
+		z = x + y
+		out = z + 5
+	`
+	parser := NewParser(strings.NewReader(flat))
+	parser.Optimize = true
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	system := ProvingSystem{
+		Name: "test",
+		KeyPoints: func(s Stats) (int, int) {
+			return s.Signals, 1
+		},
+		ProverMuls: func(s Stats) (int, int) {
+			return s.Constraints, 1
+		},
+		G1MulTime: time.Millisecond,
+		G2MulTime: 10 * time.Millisecond,
+	}
+	stats, err := circuit.Stats(system)
+	assert.Nil(t, err)
+	fmt.Println(stats)
+	assert.Equal(t, 3, stats.Constraints)
+	assert.Equal(t, 5, stats.Signals)
+	assert.Equal(t, 1, stats.PublicOutputs)
+	assert.Equal(t, 0, stats.PublicInputs)
+	assert.Equal(t, 1, stats.PrivateInputs)
+	assert.Equal(t, 2, stats.Intermediate)
+	assert.Equal(t, 5, stats.NonZeroA)
+	assert.Equal(t, 3, stats.NonZeroB)
+	assert.Equal(t, 3, stats.NonZeroC)
+	assert.Equal(t, 5*G1PointSize+G2PointSize, stats.ProvingKeySize)
+	assert.Equal(t, 13*time.Millisecond, stats.ProveTime)
+
+	// a circom circuit, with one public output, one public input, two
+	// private inputs and one intermediate wire
+	r1cs := testBinFile("r1cs", 1,
+		testSection(1, uint32(32), fqR.Q, uint32(6), uint32(1), uint32(1), uint32(2), uint64(6), uint32(0)),
+		testSection(2),
+		testSection(3, uint64(0), uint64(1), uint64(2), uint64(3), uint64(4), uint64(5)),
+	)
+	circuit, _, err = ReadR1CS(bytes.NewReader(r1cs))
+	assert.Nil(t, err)
+	stats, err = circuit.Stats(system)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, stats.Signals)
+	assert.Equal(t, 1, stats.PublicOutputs)
+	assert.Equal(t, 1, stats.PublicInputs)
+	assert.Equal(t, 2, stats.PrivateInputs)
+	assert.Equal(t, 1, stats.Intermediate)
+}
+
+func TestCircuitDOT(t *testing.T) {
+	flat := `func test(x):
+	inv <-- 1 / x
+	inv * x === 1
+	aux = x*x
+	out = aux * inv
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	err = circuit.WriteDOT(&buf)
+	assert.Nil(t, err)
+	fmt.Println(buf.String())
+	dot := buf.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph circuit {"))
+	assert.Contains(t, dot, "\t\"out\" [shape=doublecircle, style=filled, fillcolor=lightblue];")
+	assert.Contains(t, dot, "\t\"x\" [style=filled, fillcolor=lightgrey];")
+	assert.Contains(t, dot, "\t\"x\" -> \"inv\" [label=\"<-- /\", style=dashed];")
+	assert.Contains(t, dot, "\t\"assert2\" [shape=box, label=\"inv*x===1\"];")
+	assert.Contains(t, dot, "\t\"inv\" -> \"assert2\" [label=\"*\"];")
+	assert.Contains(t, dot, "\t\"x\" -> \"aux\" [label=\"*\"];")
+	assert.Contains(t, dot, "\t\"aux\" -> \"out\" [label=\"*\"];")
+}
+
+// testSection returns a section of the iden3 binary formats
+func testSection(sType uint32, values ...interface{}) []byte {
+	var content bytes.Buffer
+	for _, v := range values {
+		if n, ok := v.(*big.Int); ok {
+			// field elements, 32 bytes little endian
+			le := make([]byte, 32)
+			for i, b := range n.Bytes() {
+				le[len(n.Bytes())-1-i] = b
+			}
+			content.Write(le)
+			continue
+		}
+		binary.Write(&content, binary.LittleEndian, v)
+	}
+	var section bytes.Buffer
+	binary.Write(&section, binary.LittleEndian, sType)
+	binary.Write(&section, binary.LittleEndian, uint64(content.Len()))
+	section.Write(content.Bytes())
+	return section.Bytes()
+}
+
+func testBinFile(magic string, version uint32, sections ...[]byte) []byte {
+	var file bytes.Buffer
+	file.WriteString(magic)
+	binary.Write(&file, binary.LittleEndian, version)
+	binary.Write(&file, binary.LittleEndian, uint32(len(sections)))
+	for _, section := range sections {
+		file.Write(section)
+	}
+	return file.Bytes()
+}
+
+func TestCircuitReadCircom(t *testing.T) {
+	b1 := big.NewInt(1)
+	// out = x^3 + x + 5, with the wires one, out, x, x*x, x*x*x
+	r1cs := testBinFile("r1cs", 1,
+		testSection(1, uint32(32), fqR.Q, uint32(5), uint32(1), uint32(0), uint32(1), uint64(5), uint32(3)),
+		testSection(2,
+			// x * x = w3
+			uint32(1), uint32(2), b1, uint32(1), uint32(2), b1, uint32(1), uint32(3), b1,
+			// w3 * x = w4
+			uint32(1), uint32(3), b1, uint32(1), uint32(2), b1, uint32(1), uint32(4), b1,
+			// (5 + x + w4) * 1 = out
+			uint32(3), uint32(0), big.NewInt(5), uint32(2), b1, uint32(4), b1, uint32(1), uint32(0), b1, uint32(1), uint32(1), b1,
+		),
+		testSection(3, uint64(0), uint64(1), uint64(2), uint64(4), uint64(5)),
+	)
+	wtns := testBinFile("wtns", 2,
+		testSection(1, uint32(32), fqR.Q, uint32(5)),
+		testSection(2, b1, big.NewInt(35), big.NewInt(3), big.NewInt(9), big.NewInt(27)),
+	)
+
+	circuit, labels, err := ReadR1CS(bytes.NewReader(r1cs))
+	assert.Nil(t, err)
+	fmt.Println(circuit)
+	assert.Equal(t, []string{"one", "w1", "w2", "w3", "w4"}, circuit.Signals)
+	assert.Equal(t, []string{"w1"}, circuit.PublicSignals)
+	assert.Equal(t, []string{"w2"}, circuit.Inputs)
+	assert.Equal(t, 1, circuit.NPublic)
+	assert.Equal(t, 5, circuit.NVars)
+	assert.Equal(t, []uint64{0, 1, 2, 4, 5}, labels)
+
+	w, err := ReadWtns(bytes.NewReader(wtns))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(35), w[1])
+
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(a))
+	assert.Equal(t, []*big.Int{big.NewInt(5), big.NewInt(0), b1, big.NewInt(0), b1}, a[2])
+	assert.True(t, r1csSatisfied(a, b, c, w))
+	// the other wires are computed by the circom witness generator
+	_, err = circuit.CalculateWitness(w[2:3])
+	assert.Equal(t, "the value of w1 is computed outside of the circuit", err.Error())
+
+	_, _, err = ReadR1CS(bytes.NewReader(wtns))
+	assert.Equal(t, "not a r1cs file", err.Error())
+	_, _, err = ReadR1CS(bytes.NewReader(r1cs[:len(r1cs)-10]))
+	assert.NotNil(t, err)
+	_, err = ReadWtns(bytes.NewReader(testBinFile("wtns", 2, testSection(1, uint32(32), big.NewInt(7), uint32(0)))))
+	assert.Equal(t, "unsupported prime 7", err.Error())
+
+	// the counts of the headers are checked against the sections before
+	// allocating for them
+	header := func(nWires, nConstraints uint32) []byte {
+		return testSection(1, uint32(32), fqR.Q, nWires, uint32(0), uint32(0), uint32(0), uint64(0), nConstraints)
+	}
+	_, _, err = ReadR1CS(bytes.NewReader(testBinFile("r1cs", 1, header(0xffffffff, 0), testSection(2), testSection(3))))
+	assert.Equal(t, "r1cs wire2label section too short for 4294967295 wires", err.Error())
+	_, _, err = ReadR1CS(bytes.NewReader(testBinFile("r1cs", 1, header(1, 0xffffffff), testSection(2), testSection(3, uint64(0)))))
+	assert.Equal(t, "r1cs constraints section too short for 4294967295 constraints", err.Error())
+	_, _, err = ReadR1CS(bytes.NewReader(testBinFile("r1cs", 1, header(1, 1),
+		testSection(2, uint32(0xffffffff), uint32(0), uint32(0)), testSection(3, uint64(0)))))
+	assert.Equal(t, "r1cs constraints section too short for 4294967295 factors", err.Error())
+	_, _, err = ReadR1CS(bytes.NewReader(testBinFile("r1cs", 1, header(1, 0), testSection(2))))
+	assert.Equal(t, "r1cs wire2label section not found", err.Error())
+	// the public and private wires must fit in the wires, after one
+	inputs := func(nWires, nPubOut, nPubIn, nPrvIn uint32) []byte {
+		return testBinFile("r1cs", 1, testSection(1, uint32(32), fqR.Q, nWires, nPubOut, nPubIn, nPrvIn, uint64(0), uint32(0)),
+			testSection(2), testSection(3, uint64(0), uint64(1), uint64(2), uint64(3)))
+	}
+	_, _, err = ReadR1CS(bytes.NewReader(inputs(4, 1, 1, 2)))
+	assert.Equal(t, "invalid number of wires in the r1cs header", err.Error())
+	_, _, err = ReadR1CS(bytes.NewReader(inputs(4, 0, 0, 0xffffffff)))
+	assert.Equal(t, "invalid number of wires in the r1cs header", err.Error())
+	circuit, _, err = ReadR1CS(bytes.NewReader(inputs(4, 1, 1, 1)))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"w1", "w2"}, circuit.PublicSignals)
+	assert.Equal(t, []string{"w2", "w3"}, circuit.Inputs)
+	_, err = ReadWtns(bytes.NewReader(testBinFile("wtns", 2, testSection(1, uint32(32), fqR.Q, uint32(0xffffffff)), testSection(2))))
+	assert.Equal(t, "wtns witness section too short for 4294967295 values", err.Error())
+}
+
+func TestCircuitWriteCircom(t *testing.T) {
+	flat := `func test(x, y):
+	aux = x * y
+	z = y * y
+	out = aux + z
+	q = out * out
+	q === 121
+`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(10), big.NewInt(1)})
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	assert.True(t, r1csSatisfied(a, b, c, w))
+
+	var r1cs, wtns bytes.Buffer
+	err = circuit.WriteR1CS(&r1cs)
+	assert.Nil(t, err)
+	err = circuit.WriteWtns(&wtns, w)
+	assert.Nil(t, err)
+
+	imported, labels, err := ReadR1CS(&r1cs)
+	assert.Nil(t, err)
+	assert.Equal(t, len(circuit.Signals), len(labels))
+	assert.Equal(t, circuit.NPublic, imported.NPublic)
+	importedW, err := ReadWtns(&wtns)
+	assert.Nil(t, err)
+	// the signals are already in the circom order: one, out, x, y, ...
+	assert.Equal(t, w, importedW)
+
+	importedA, importedB, importedC, err := imported.GenerateR1CS()
+	assert.Nil(t, err)
+	assert.Equal(t, a, importedA)
+	assert.Equal(t, b, importedB)
+	assert.Equal(t, c, importedC)
+	assert.True(t, r1csSatisfied(importedA, importedB, importedC, importedW))
+}
@@ -0,0 +1,156 @@
+package circuitcompiler
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxComparisonBits is the maximum bit length of the operands of the ordering
+// comparisons, as v1 - v2 + 2^n must fit in the 253 bits of the field
+const maxComparisonBits = 252
+
+// internalPrefix starts the names of the signals added by the compiler. The
+// lexer rejects it in the circuit code, so they can not collide with the
+// signals of the code
+const internalPrefix = "_"
+
+// internalSignal returns the name of an auxiliary signal of out
+func internalSignal(out, name string) string {
+	if !strings.HasPrefix(out, internalPrefix) {
+		out = internalPrefix + out
+	}
+	return out + "_" + name
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		return true
+	}
+	return false
+}
+
+func isOrderingComparison(op string) bool {
+	return op == "<" || op == "<=" || op == ">" || op == ">="
+}
+
+// expandComparison flattens the comparison `out = v1 op v2` into constraints
+// that fit in the R1CS, leaving out constrained to 0 (false) or 1 (true). The
+// operands of the ordering comparisons must be lower than 2^bits
+func expandComparison(c Constraint, bits int) []Constraint {
+	switch c.Op {
+	case "<":
+		return lessThan(c.Out, c.V1, c.V2, bits)
+	case "<=":
+		return greaterOrEqual(c.Out, c.V2, c.V1, bits)
+	case ">":
+		return lessThan(c.Out, c.V2, c.V1, bits)
+	case ">=":
+		return greaterOrEqual(c.Out, c.V1, c.V2, bits)
+	case "==":
+		return isEqual(c.Out, c.V1, c.V2)
+	case "!=":
+		return isNotEqual(c.Out, c.V1, c.V2)
+	}
+	return nil
+}
+
+func newConstraint(op, v1, v2, out string) Constraint {
+	return Constraint{
+		Op:      op,
+		V1:      v1,
+		V2:      v2,
+		Out:     out,
+		Literal: out + "=" + v1 + op + v2,
+	}
+}
+
+func newAssert(op, v1, v2, out string) Constraint {
+	return Constraint{
+		Op:      op,
+		V1:      v1,
+		V2:      v2,
+		Out:     out,
+		Literal: v1 + op + v2 + "===" + out,
+		Assert:  true,
+	}
+}
+
+// newHint computes in the witness out = fn(inputs), it must be constrained
+// by other constraints
+func newHint(fn, out string, inputs ...string) Constraint {
+	return Constraint{
+		Op:      fn,
+		Out:     out,
+		Inputs:  inputs,
+		Literal: out + "<--" + fn + "(" + strings.Join(inputs, ",") + ")",
+		Hint:    true,
+	}
+}
+
+// greaterOrEqual decomposes d = v1 - v2 + 2^n in n+1 bits, being the bit n
+// the result of v1 >= v2
+func greaterOrEqual(out, v1, v2 string, n int) []Constraint {
+	shift := new(big.Int).Lsh(big.NewInt(int64(1)), uint(n))
+	d := internalSignal(out, "diff")
+
+	var cs []Constraint
+	cs = append(cs, newConstraint("+", v1, shift.String(), internalSignal(out, "shift")))
+	cs = append(cs, newConstraint("-", internalSignal(out, "shift"), v2, d))
+
+	var bits []string
+	for i := 0; i <= n; i++ {
+		bit := internalSignal(out, "b"+strconv.Itoa(i))
+		if i == n {
+			bit = out
+		}
+		bits = append(bits, bit)
+		// the bit is computed in the witness, and constrained to be 0 or 1
+		cs = append(cs, newHint("bit", bit, d, strconv.Itoa(i)))
+		cs = append(cs, newAssert("*", bit, bit, bit))
+	}
+	// the bits must compose d
+	cs = append(cs, Constraint{
+		Op:      "pack",
+		Out:     d,
+		Inputs:  bits,
+		Literal: "pack(" + internalSignal(out, "b") + ")===" + d,
+		Assert:  true,
+	})
+	return cs
+}
+
+func lessThan(out, v1, v2 string, n int) []Constraint {
+	geq := internalSignal(out, "geq")
+	cs := greaterOrEqual(geq, v1, v2, n)
+	cs = append(cs, newConstraint("-", "1", geq, out))
+	return cs
+}
+
+// isEqual uses the inverse of d = v1 - v2, which only exists when d != 0:
+// out = 1 - d * inv(d), and d * out === 0
+func isEqual(out, v1, v2 string) []Constraint {
+	d := internalSignal(out, "diff")
+	inv := internalSignal(out, "inv")
+	return []Constraint{
+		newConstraint("-", v1, v2, d),
+		newHint("inv", inv, d),
+		newConstraint("*", d, inv, internalSignal(out, "nz")),
+		newConstraint("-", "1", internalSignal(out, "nz"), out),
+		newAssert("*", d, out, "0"),
+	}
+}
+
+// isNotEqual is the negation of isEqual: out = d * inv(d), and d * (1 - out) === 0
+func isNotEqual(out, v1, v2 string) []Constraint {
+	d := internalSignal(out, "diff")
+	inv := internalSignal(out, "inv")
+	return []Constraint{
+		newConstraint("-", v1, v2, d),
+		newHint("inv", inv, d),
+		newConstraint("*", d, inv, out),
+		newConstraint("-", "1", out, internalSignal(out, "z")),
+		newAssert("*", d, internalSignal(out, "z"), "0"),
+	}
+}
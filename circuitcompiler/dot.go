@@ -0,0 +1,64 @@
+package circuitcompiler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteDOT writes the dataflow graph of the Circuit in the Graphviz DOT
+// format. The nodes are the signals, and each constraint adds edges from the
+// signals it uses to the signal it assigns, labelled by its operation. The
+// hints are dashed edges, and the assertions are box nodes. The public
+// signals are drawn as double circles, and the inputs filled
+func (circ *Circuit) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph circuit {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+
+	inputs := make(map[string]bool)
+	for _, in := range circ.Inputs {
+		inputs[in] = true
+	}
+	for _, s := range circ.Signals[1:] {
+		attrs := ""
+		switch {
+		case existInArray(circ.PublicSignals, s):
+			attrs = " [shape=doublecircle, style=filled, fillcolor=lightblue]"
+		case inputs[s]:
+			attrs = " [style=filled, fillcolor=lightgrey]"
+		}
+		fmt.Fprintf(bw, "\t%s%s;\n", strconv.Quote(s), attrs)
+	}
+
+	for i, c := range circ.Constraints {
+		if c.Op == "in" || c.Op == "wire" {
+			continue
+		}
+		label := c.Op
+		style := ""
+		if c.Hint {
+			label = "<-- " + c.Op
+			style = ", style=dashed"
+		}
+		to := c.Out
+		if c.Assert {
+			to = "assert" + strconv.Itoa(i)
+			fmt.Fprintf(bw, "\t%s [shape=box, label=%s];\n", strconv.Quote(to), strconv.Quote(c.Literal))
+		}
+		for _, from := range uniqueSignals(c.reads()) {
+			fmt.Fprintf(bw, "\t%s -> %s [label=%s%s];\n", strconv.Quote(from), strconv.Quote(to), strconv.Quote(label), style)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func uniqueSignals(signals []string) []string {
+	var r []string
+	for _, s := range signals {
+		r = addToArrayIfNotExist(r, s)
+	}
+	return r
+}
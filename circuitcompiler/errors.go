@@ -0,0 +1,44 @@
+package circuitcompiler
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Error is an error found while parsing or compiling the circuit, at the given
+// position of the circuit code
+type Error struct {
+	Pos   Position
+	Token string // offending token
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg + " (" + strconv.Quote(e.Token) + ")"
+}
+
+// ErrorList is the list of the errors found while parsing or compiling the circuit
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Add adds a new Error to the list
+func (l *ErrorList) Add(pos Position, token, msg string) {
+	*l = append(*l, &Error{Pos: pos, Token: token, Msg: msg})
+}
+
+// Err returns nil when the list is empty, or the list as an error otherwise
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
@@ -0,0 +1,98 @@
+package circuitcompiler
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// HintFunc computes a witness value from the given input values, outside of
+// the constraint system. The circuit must constrain the returned value with
+// assertions, as the prover is free to choose any other value
+type HintFunc func(inputs []*big.Int) (*big.Int, error)
+
+var hints = struct {
+	sync.RWMutex
+	funcs map[string]HintFunc
+}{
+	funcs: map[string]HintFunc{
+		"inv":  hintInverse,
+		"bit":  hintBit,
+		"idiv": hintIntDiv,
+		"mod":  hintIntMod,
+	},
+}
+
+// RegisterHint registers a hint function, that then can be used in the
+// circuits as `out <-- name(in0, in1, ...)`
+func RegisterHint(name string, f HintFunc) error {
+	hints.Lock()
+	defer hints.Unlock()
+	if _, ok := hints.funcs[name]; ok {
+		return errors.New("hint already registered: " + name)
+	}
+	hints.funcs[name] = f
+	return nil
+}
+
+func getHint(name string) (HintFunc, error) {
+	hints.RLock()
+	defer hints.RUnlock()
+	f, ok := hints.funcs[name]
+	if !ok {
+		return nil, errors.New("hint not registered: " + name)
+	}
+	return f, nil
+}
+
+func checkHintInputs(inputs []*big.Int, n int) error {
+	if len(inputs) != n {
+		return errors.New("wrong number of hint inputs")
+	}
+	return nil
+}
+
+// hintInverse returns 1/in[0], or 0 when in[0] is 0
+func hintInverse(inputs []*big.Int) (*big.Int, error) {
+	if err := checkHintInputs(inputs, 1); err != nil {
+		return nil, err
+	}
+	v := fqR.Affine(inputs[0])
+	if fqR.IsZero(v) {
+		return fqR.Zero(), nil
+	}
+	return fqR.Inverse(v), nil
+}
+
+// hintBit returns the bit in[1] of in[0]
+func hintBit(inputs []*big.Int) (*big.Int, error) {
+	if err := checkHintInputs(inputs, 2); err != nil {
+		return nil, err
+	}
+	v := fqR.Affine(inputs[0])
+	return big.NewInt(int64(v.Bit(int(inputs[1].Int64())))), nil
+}
+
+// hintIntDiv returns the integer quotient of in[0] / in[1]
+func hintIntDiv(inputs []*big.Int) (*big.Int, error) {
+	if err := checkHintInputs(inputs, 2); err != nil {
+		return nil, err
+	}
+	d := fqR.Affine(inputs[1])
+	if fqR.IsZero(d) {
+		return nil, errors.New("division by zero")
+	}
+	return new(big.Int).Div(fqR.Affine(inputs[0]), d), nil
+}
+
+// hintIntMod returns the remainder of the integer division in[0] / in[1]
+func hintIntMod(inputs []*big.Int) (*big.Int, error) {
+	if err := checkHintInputs(inputs, 2); err != nil {
+		return nil, err
+	}
+	d := fqR.Affine(inputs[1])
+	if fqR.IsZero(d) {
+		return nil, errors.New("division by zero")
+	}
+	return new(big.Int).Mod(fqR.Affine(inputs[0]), d), nil
+}
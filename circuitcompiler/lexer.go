@@ -3,6 +3,7 @@ package circuitcompiler
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 )
 
@@ -12,12 +13,16 @@ type Token int
 const (
 	ILLEGAL Token = iota
 	WS
+	NEWLINE
+	COMMENT
 	EOF
 
 	IDENT // val
 
-	VAR   // var
-	CONST // const value
+	VAR    // var
+	CONST  // const value
+	STRING // "string"
+	IMPORT // import
 
 	EQ       // =
 	PLUS     // +
@@ -26,13 +31,27 @@ const (
 	DIVIDE   // /
 	EXP      // ^
 
+	LT   // <
+	LEQ  // <=
+	GT   // >
+	GEQ  // >=
+	EQEQ // ==
+	NEQ  // !=
+
+	HINT   // <--
+	ASSERT // ===
+	LPAREN // (
+	RPAREN // )
+	COMMA  // ,
+	COLON  // :
+
 	OUT
 )
 
 var eof = rune(0)
 
 func isWhitespace(ch rune) bool {
-	return ch == ' ' || ch == '\t' || ch == '\n'
+	return ch == ' ' || ch == '\t' || ch == '\r'
 }
 
 func isLetter(ch rune) bool {
@@ -42,14 +61,34 @@ func isDigit(ch rune) bool {
 	return (ch >= '0' && ch <= '9')
 }
 
+// Position is the line and column of a token in the circuit code, both
+// starting at 1, and the file of the code when it is read from a file
+type Position struct {
+	File   string `json:",omitempty"`
+	Line   int
+	Column int
+}
+
+func (pos Position) String() string {
+	if pos.File != "" {
+		return fmt.Sprintf("%s:%d:%d", pos.File, pos.Line, pos.Column)
+	}
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
 // Scanner holds the bufio.Reader
 type Scanner struct {
-	r *bufio.Reader
+	r       *bufio.Reader
+	pos     Position // position of the next rune
+	prevPos Position // position before the last read, to unread
 }
 
 // NewScanner creates a new Scanner with the given io.Reader
 func NewScanner(r io.Reader) *Scanner {
-	return &Scanner{r: bufio.NewReader(r)}
+	return &Scanner{
+		r:   bufio.NewReader(r),
+		pos: Position{Line: 1, Column: 1},
+	}
 }
 
 func (s *Scanner) read() rune {
@@ -57,34 +96,69 @@ func (s *Scanner) read() rune {
 	if err != nil {
 		return eof
 	}
+	s.prevPos = s.pos
+	if ch == '\n' {
+		s.pos.Line++
+		s.pos.Column = 1
+	} else {
+		s.pos.Column++
+	}
 	return ch
 }
 
 func (s *Scanner) unread() {
-	_ = s.r.UnreadRune()
+	if err := s.r.UnreadRune(); err == nil {
+		s.pos = s.prevPos
+	}
+}
+
+// readIf consumes the next rune only if it is the expected one
+func (s *Scanner) readIf(expected rune) bool {
+	if ch := s.read(); ch != expected {
+		s.unread()
+		return false
+	}
+	return true
 }
 
-// Scan returns the Token and literal string of the current value
-func (s *Scanner) scan() (tok Token, lit string) {
+// Scan returns the Token, literal string and position of the current value
+func (s *Scanner) scan() (tok Token, lit string, pos Position) {
+	pos = s.pos
 	ch := s.read()
 
 	if isWhitespace(ch) {
 		// space
 		s.unread()
-		return s.scanWhitespace()
+		tok, lit = s.scanWhitespace()
+		return tok, lit, pos
 	} else if isLetter(ch) {
 		// letter
 		s.unread()
-		return s.scanIndent()
+		tok, lit = s.scanIndent()
+		return tok, lit, pos
 	} else if isDigit(ch) {
 		s.unread()
-		return s.scanIndent()
+		tok, lit = s.scanNumber()
+		return tok, lit, pos
 	}
 
+	tok, lit = s.scanSymbol(ch)
+	return tok, lit, pos
+}
+
+func (s *Scanner) scanSymbol(ch rune) (tok Token, lit string) {
 	switch ch {
 	case eof:
 		return EOF, ""
+	case '\n':
+		return NEWLINE, "\n"
 	case '=':
+		if s.readIf('=') {
+			if s.readIf('=') {
+				return ASSERT, "==="
+			}
+			return EQEQ, "=="
+		}
 		return EQ, "="
 	case '+':
 		return PLUS, "+"
@@ -93,9 +167,44 @@ func (s *Scanner) scan() (tok Token, lit string) {
 	case '*':
 		return MULTIPLY, "*"
 	case '/':
+		if s.readIf('/') {
+			return s.scanComment("//")
+		}
 		return DIVIDE, "/"
+	case '#':
+		return s.scanComment("#")
+	case '"':
+		return s.scanString()
 	case '^':
 		return EXP, "^"
+	case '<':
+		if s.readIf('=') {
+			return LEQ, "<="
+		}
+		if s.readIf('-') {
+			if s.readIf('-') {
+				return HINT, "<--"
+			}
+			return ILLEGAL, "<-"
+		}
+		return LT, "<"
+	case '>':
+		if s.readIf('=') {
+			return GEQ, ">="
+		}
+		return GT, ">"
+	case '!':
+		if s.readIf('=') {
+			return NEQ, "!="
+		}
+	case '(':
+		return LPAREN, "("
+	case ')':
+		return RPAREN, ")"
+	case ',':
+		return COMMA, ","
+	case ':':
+		return COLON, ":"
 	}
 
 	return ILLEGAL, string(ch)
@@ -118,6 +227,43 @@ func (s *Scanner) scanWhitespace() (token Token, lit string) {
 	return WS, buf.String()
 }
 
+// scanComment scans the comment until the end of the line
+func (s *Scanner) scanComment(start string) (tok Token, lit string) {
+	var buf bytes.Buffer
+	buf.WriteString(start)
+
+	for {
+		if ch := s.read(); ch == eof {
+			break
+		} else if ch == '\n' {
+			s.unread()
+			break
+		} else {
+			_, _ = buf.WriteRune(ch)
+		}
+	}
+	return COMMENT, buf.String()
+}
+
+// scanString scans a string until the closing quote, returning the string
+// without the quotes
+func (s *Scanner) scanString() (tok Token, lit string) {
+	var buf bytes.Buffer
+
+	for {
+		if ch := s.read(); ch == eof || ch == '\n' {
+			s.unread()
+			// not terminated
+			return ILLEGAL, "\"" + buf.String()
+		} else if ch == '"' {
+			break
+		} else {
+			_, _ = buf.WriteRune(ch)
+		}
+	}
+	return STRING, buf.String()
+}
+
 func (s *Scanner) scanIndent() (tok Token, lit string) {
 	var buf bytes.Buffer
 	buf.WriteRune(s.read())
@@ -135,13 +281,32 @@ func (s *Scanner) scanIndent() (tok Token, lit string) {
 	switch buf.String() {
 	case "var":
 		return VAR, buf.String()
-	}
-
-	if len(buf.String()) == 1 {
-		return Token(rune(buf.String()[0])), buf.String()
-	}
-	if buf.String() == "out" {
+	case "out":
 		return OUT, buf.String()
+	case "import":
+		return IMPORT, buf.String()
 	}
 	return IDENT, buf.String()
 }
+
+func (s *Scanner) scanNumber() (tok Token, lit string) {
+	var buf bytes.Buffer
+	buf.WriteRune(s.read())
+
+	tok = CONST
+	for {
+		if ch := s.read(); ch == eof {
+			break
+		} else if !isLetter(ch) && !isDigit(ch) {
+			s.unread()
+			break
+		} else {
+			if !isDigit(ch) {
+				// identifiers can not start with a digit
+				tok = ILLEGAL
+			}
+			_, _ = buf.WriteRune(ch)
+		}
+	}
+	return tok, buf.String()
+}
@@ -0,0 +1,229 @@
+package circuitcompiler
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Term is a signal multiplied by a coefficient
+type Term struct {
+	Coeff  *big.Int
+	Signal string
+}
+
+// LinearCombination is a sum of terms, where the constant values are terms of
+// the signal "one"
+type LinearCombination []Term
+
+// newLinearCombination returns the linear combination of the value or signal v
+func newLinearCombination(v string) LinearCombination {
+	lc := LinearCombination{}
+	if isVal, value := isValue(v); isVal {
+		return lc.add("one", value)
+	}
+	return lc.add(v, big.NewInt(int64(1)))
+}
+
+// add returns the linear combination plus coeff * signal
+func (lc LinearCombination) add(signal string, coeff *big.Int) LinearCombination {
+	r := LinearCombination{}
+	found := false
+	for _, term := range lc {
+		if term.Signal == signal {
+			term.Coeff = fqR.Add(term.Coeff, coeff)
+			found = true
+		}
+		if !fqR.IsZero(fqR.Affine(term.Coeff)) {
+			r = append(r, Term{Coeff: fqR.Affine(term.Coeff), Signal: term.Signal})
+		}
+	}
+	if !found && !fqR.IsZero(fqR.Affine(coeff)) {
+		r = append(r, Term{Coeff: fqR.Affine(coeff), Signal: signal})
+	}
+	return r
+}
+
+// Add returns lc + other
+func (lc LinearCombination) Add(other LinearCombination) LinearCombination {
+	r := append(LinearCombination{}, lc...)
+	for _, term := range other {
+		r = r.add(term.Signal, term.Coeff)
+	}
+	return r
+}
+
+// MulScalar returns the linear combination multiplied by k
+func (lc LinearCombination) MulScalar(k *big.Int) LinearCombination {
+	r := LinearCombination{}
+	for _, term := range lc {
+		r = r.add(term.Signal, fqR.Mul(term.Coeff, k))
+	}
+	return r
+}
+
+// constant returns the value of the linear combination if it only has the
+// constant term
+func (lc LinearCombination) constant() (bool, *big.Int) {
+	switch {
+	case len(lc) == 0:
+		return true, big.NewInt(int64(0))
+	case len(lc) == 1 && lc[0].Signal == "one":
+		return true, lc[0].Coeff
+	}
+	return false, nil
+}
+
+func (lc LinearCombination) String() string {
+	if len(lc) == 0 {
+		return "0"
+	}
+	half := new(big.Int).Rsh(fqR.Q, 1)
+	var s strings.Builder
+	for i, term := range lc {
+		coeff := term.Coeff
+		if coeff.Cmp(half) > 0 {
+			s.WriteString("-")
+			coeff = fqR.Neg(coeff)
+		} else if i > 0 {
+			s.WriteString("+")
+		}
+		if term.Signal == "one" {
+			s.WriteString(coeff.String())
+			continue
+		}
+		if coeff.Cmp(big.NewInt(int64(1))) != 0 {
+			s.WriteString(coeff.String() + "*")
+		}
+		s.WriteString(term.Signal)
+	}
+	return s.String()
+}
+
+// evalLinearCombination returns the value of the linear combination over the witness
+func (circ *Circuit) evalLinearCombination(lc LinearCombination, w []*big.Int) *big.Int {
+	r := big.NewInt(int64(0))
+	for _, term := range lc {
+		r = fqR.Add(r, fqR.Mul(term.Coeff, w[indexInArray(circ.Signals, term.Signal)]))
+	}
+	return r
+}
+
+// linear returns the linear combination computed by the constraint, if it is
+// an addition, subtraction, or multiplication or division by a constant
+func linear(op string, v1, v2 LinearCombination) (bool, LinearCombination) {
+	isConst1, k1 := v1.constant()
+	isConst2, k2 := v2.constant()
+	switch {
+	case op == "+":
+		return true, v1.Add(v2)
+	case op == "-":
+		return true, v1.Add(v2.MulScalar(big.NewInt(int64(-1))))
+	case op == "*" && isConst1:
+		return true, v2.MulScalar(k1)
+	case op == "*" && isConst2:
+		return true, v1.MulScalar(k2)
+	case op == "/" && isConst2 && !fqR.IsZero(fqR.Affine(k2)):
+		return true, v1.MulScalar(fqR.Inverse(fqR.Affine(k2)))
+	}
+	return false, nil
+}
+
+// foldLinear folds the linear constraints (additions, subtractions, and
+// multiplications and divisions by constants) into the linear combinations of
+// the constraints that use their out signal, removing the signal. The signals
+// used by hints or bit packing, the public signals and the signals that are
+// not used are kept
+func (circ *Circuit) foldLinear() {
+	foldable := make(map[string]bool)
+	for _, c := range circ.Constraints {
+		if c.Op == "in" || c.Hint {
+			continue
+		}
+		if c.Op == "pack" && c.Assert {
+			foldable[c.Out] = true
+			continue
+		}
+		for _, v := range []string{c.V1, c.V2} {
+			if isSignal(v) {
+				foldable[v] = true
+			}
+		}
+		if c.Assert {
+			foldable[c.Out] = true
+		}
+	}
+	for _, c := range circ.Constraints {
+		if c.Op == "in" || c.Hint || !isOperator(c.Op) {
+			for _, v := range append([]string{c.V1, c.V2}, c.Inputs...) {
+				foldable[v] = false
+			}
+		}
+	}
+	foldable["out"] = false
+
+	folded := make(map[string]LinearCombination)
+	operand := func(v string) (LinearCombination, bool) {
+		if lc, ok := folded[v]; ok {
+			return lc, true
+		}
+		return newLinearCombination(v), false
+	}
+
+	var constraints []Constraint
+	for _, c := range circ.Constraints {
+		if c.Op == "in" || c.Hint {
+			constraints = append(constraints, c)
+			continue
+		}
+		if c.Op == "pack" {
+			if lc, ok := operand(c.Out); ok && c.Assert {
+				c.LinOut = lc
+				c.Out = ""
+				c.Literal = c.Literal[:strings.Index(c.Literal, "===")] + "===" + lc.String()
+			}
+			constraints = append(constraints, c)
+			continue
+		}
+		if !isOperator(c.Op) {
+			constraints = append(constraints, c)
+			continue
+		}
+
+		lin1, ok1 := operand(c.V1)
+		lin2, ok2 := operand(c.V2)
+		linOut, okOut := newLinearCombination(c.Out), false
+		if c.Assert {
+			linOut, okOut = operand(c.Out)
+		}
+		isLinear, lc := linear(c.Op, lin1, lin2)
+		if isLinear && !c.Assert && foldable[c.Out] {
+			folded[c.Out] = lc
+			continue
+		}
+		if !ok1 && !ok2 && !okOut {
+			// nothing folded into the constraint
+			constraints = append(constraints, c)
+			continue
+		}
+
+		if isLinear {
+			// lc * 1 = out
+			c.Op = "*"
+			lin1 = lc
+			lin2 = newLinearCombination("1")
+		}
+		c.V1 = ""
+		c.V2 = ""
+		c.Lin1 = lin1
+		c.Lin2 = lin2
+		if c.Assert {
+			c.LinOut = linOut
+			c.Out = ""
+			c.Literal = "(" + lin1.String() + ")" + c.Op + "(" + lin2.String() + ")===" + linOut.String()
+		} else {
+			c.Literal = c.Out + "=(" + lin1.String() + ")" + c.Op + "(" + lin2.String() + ")"
+		}
+		constraints = append(constraints, c)
+	}
+	circ.Constraints = constraints
+}
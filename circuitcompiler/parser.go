@@ -1,25 +1,65 @@
 package circuitcompiler
 
 import (
-	"errors"
+	"bytes"
 	"io"
-	"regexp"
-	"strings"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
 )
 
 // Parser data structure holds the Scanner and the Parsing functions
 type Parser struct {
 	s   *Scanner
 	buf struct {
-		tok Token  // last read token
-		lit string // last read literal
-		n   int    // buffer size (max=1)
+		tok Token    // last read token
+		lit string   // last read literal
+		pos Position // last read position
+		n   int      // buffer size (max=1)
 	}
+
+	path       string          // path of the circuit file, to resolve the imports
+	isImported bool            // the file is imported from another file
+	stack      []string        // files being imported, to detect the cycles
+	imported   map[string]bool // files already imported
+
+	// Optimize folds the linear constraints into the constraints that use
+	// them, and removes the constraints that do not contribute to the outputs
+	// nor the assertions. It is disabled by default
+	Optimize bool
+	// ComparisonBits is the bit length of the operands of the ordering
+	// comparisons (<, <=, >, >=), which must be lower than 2^ComparisonBits.
+	// It is 64 by default, and at most 252
+	ComparisonBits int
 }
 
-// NewParser creates a new parser from a io.Reader
+// NewParser creates a new parser from a io.Reader. The imports of the code
+// are resolved relative to the working directory
 func NewParser(r io.Reader) *Parser {
-	return &Parser{s: NewScanner(r)}
+	return &Parser{
+		s:              NewScanner(r),
+		imported:       make(map[string]bool),
+		ComparisonBits: 64,
+	}
+}
+
+// NewFileParser creates a new parser reading the circuit file at the given
+// path. The imports of the code are resolved relative to the file
+func NewFileParser(path string) (*Parser, error) {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	p := NewParser(bytes.NewReader(code))
+	p.s.pos.File = path
+	p.path = path
+	p.stack = []string{absPath}
+	p.imported[absPath] = true
+	return p, nil
 }
 
 func (p *Parser) scan() (tok Token, lit string) {
@@ -28,9 +68,9 @@ func (p *Parser) scan() (tok Token, lit string) {
 		p.buf.n = 0
 		return p.buf.tok, p.buf.lit
 	}
-	tok, lit = p.s.scan()
+	tok, lit, pos := p.s.scan()
 
-	p.buf.tok, p.buf.lit = tok, lit
+	p.buf.tok, p.buf.lit, p.buf.pos = tok, lit, pos
 
 	return
 }
@@ -41,58 +81,263 @@ func (p *Parser) unscan() {
 
 func (p *Parser) scanIgnoreWhitespace() (tok Token, lit string) {
 	tok, lit = p.scan()
-	if tok == WS {
+	for tok == WS || tok == COMMENT {
 		tok, lit = p.scan()
 	}
 	return
 }
 
-// parseLine parses the current line
-func (p *Parser) parseLine() (*Constraint, error) {
+// pos returns the position of the last read token
+func (p *Parser) pos() Position {
+	return p.buf.pos
+}
+
+func (p *Parser) newError(lit, msg string) *Error {
+	if p.buf.tok == ILLEGAL {
+		msg = "illegal token, " + msg
+	}
+	return &Error{Pos: p.pos(), Token: lit, Msg: msg}
+}
+
+func isIdent(tok Token) bool {
+	return tok == IDENT || tok == OUT
+}
+
+func isOperand(tok Token) bool {
+	return isIdent(tok) || tok == CONST
+}
+
+func isArithmeticOperator(tok Token) bool {
+	return tok == PLUS || tok == MINUS || tok == MULTIPLY || tok == DIVIDE
+}
+
+func isComparisonOperator(tok Token) bool {
+	return tok == LT || tok == LEQ || tok == GT || tok == GEQ || tok == EQEQ || tok == NEQ
+}
+
+// parseLine parses the current line, returning nil, nil at the end of the code
+func (p *Parser) parseLine() (*Constraint, *Error) {
 	/*
 		in this version,
 		line will be for example s3 = s1 * s4
 		this is:
 		val eq val op val
+
+		also can be a hint, computed in the witness but not constrained:
+		val <-- val op val
+		val <-- fn(val, val)
+
+		or an assertion, constrained but not assigned:
+		val op val === val
 	*/
 	c := &Constraint{}
 	tok, lit := p.scanIgnoreWhitespace()
+	for tok == NEWLINE {
+		// empty line
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+	if tok == EOF {
+		return nil, nil
+	}
+	c.Pos = p.pos()
+
+	if tok == IDENT && lit == "func" {
+		return p.parseFunc(c)
+	}
+	if tok == IMPORT {
+		// format: `import "path"`
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok != STRING {
+			return nil, p.newError(lit, "expected the path of the file to import")
+		}
+		c.Literal = "import"
+		c.V1 = lit
+		return c, p.expectEndOfLine()
+	}
+	if !isOperand(tok) {
+		return nil, p.newError(lit, "expected a signal or a value")
+	}
+	outTok, outLit := tok, lit
 	c.Out = lit
 	c.Literal += lit
 
-	if c.Literal == "func" {
-		// format: `func name(in):`
-		line, err := p.s.r.ReadString(':')
-		if err != nil {
-			return c, err
+	tok, lit = p.scanIgnoreWhitespace() // =, <-- or the operator of an assertion
+	c.Literal += lit
+	var err *Error
+	switch {
+	case tok == EQ || tok == HINT:
+		if !isIdent(outTok) {
+			return nil, &Error{Pos: c.Pos, Token: outLit, Msg: "can not assign to a value"}
+		}
+		err = p.parseAssignment(c, tok == HINT)
+	case tok == ASSERT || isArithmeticOperator(tok):
+		err = p.parseAssertion(c, tok, lit)
+	default:
+		err = p.newError(lit, "expected =, <-- or an operator")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, p.expectEndOfLine()
+}
+
+// parseFunc parses the function declaration, `func name(in0, in1, ...):`
+func (p *Parser) parseFunc(c *Constraint) (*Constraint, *Error) {
+	c.Literal = "func"
+	tok, lit := p.scanIgnoreWhitespace()
+	if tok != IDENT {
+		return nil, p.newError(lit, "expected the function name")
+	}
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok != LPAREN {
+		return nil, p.newError(lit, "expected (")
+	}
+	for {
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == RPAREN && len(c.Inputs) == 0 {
+			break
+		}
+		if tok != IDENT {
+			return nil, p.newError(lit, "expected an input signal")
+		}
+		c.Inputs = append(c.Inputs, lit)
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == RPAREN {
+			break
+		}
+		if tok != COMMA {
+			return nil, p.newError(lit, "expected , or )")
 		}
-		// read string inside ( )
-		rgx := regexp.MustCompile(`\((.*?)\)`)
-		insideParenthesis := rgx.FindStringSubmatch(line)
-		varsString := strings.Replace(insideParenthesis[1], " ", "", -1)
-		c.Inputs = strings.Split(varsString, ",")
-		return c, nil
 	}
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok != COLON {
+		return nil, p.newError(lit, "expected :")
+	}
+	return c, p.expectEndOfLine()
+}
 
-	_, lit = p.scanIgnoreWhitespace() // skip =
+// parseOperand parses a signal or a value
+func (p *Parser) parseOperand(c *Constraint) (string, *Error) {
+	tok, lit := p.scanIgnoreWhitespace()
+	if !isOperand(tok) {
+		return "", p.newError(lit, "expected a signal or a value")
+	}
 	c.Literal += lit
+	return lit, nil
+}
 
+// parseAssignment parses the rest of an assignment line, `out = v1 op v2`,
+// `out <-- v1 op v2` or `out <-- fn(in0, in1, ...)`, being out already in c.Out
+func (p *Parser) parseAssignment(c *Constraint, hint bool) *Error {
+	c.Hint = hint
 	// v1
-	_, lit = p.scanIgnoreWhitespace()
-	c.V1 = lit
-	c.Literal += lit
+	v1, err := p.parseOperand(c)
+	if err != nil {
+		return err
+	}
+	c.V1 = v1
 	// operator
-	_, lit = p.scanIgnoreWhitespace()
+	tok, lit := p.scanIgnoreWhitespace()
+	if hint && tok == LPAREN {
+		// hint function call, where v1 is the function name
+		c.Literal += lit
+		c.Op = c.V1
+		c.V1 = ""
+		return p.parseHintArgs(c)
+	}
+	if isComparisonOperator(tok) && hint {
+		return p.newError(lit, "comparisons can not be used in hints")
+	}
+	if !isArithmeticOperator(tok) && !isComparisonOperator(tok) {
+		return p.newError(lit, "expected an operator")
+	}
 	c.Op = lit
 	c.Literal += lit
 	// v2
-	_, lit = p.scanIgnoreWhitespace()
-	c.V2 = lit
-	c.Literal += lit
+	c.V2, err = p.parseOperand(c)
+	return err
+}
+
+// parseAssertion parses the rest of an assertion line, `v1 op v2 === out`
+// or `v1 === out`, being v1 already in c.Out
+func (p *Parser) parseAssertion(c *Constraint, tok Token, lit string) *Error {
+	c.V1 = c.Out
+	c.Assert = true
+	if tok == ASSERT {
+		// v1 === out, as v1 * 1 === out
+		c.Op = "*"
+		c.V2 = "1"
+	} else {
+		c.Op = lit
+		// v2
+		v2, err := p.parseOperand(c)
+		if err != nil {
+			return err
+		}
+		c.V2 = v2
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok != ASSERT {
+			return p.newError(lit, "expected === in assertion")
+		}
+		c.Literal += lit
+	}
+	// out
+	out, err := p.parseOperand(c)
+	if err != nil {
+		return err
+	}
+	c.Out = out
+	return nil
+}
+
+// parseHintArgs parses the arguments of a hint function call, until the )
+func (p *Parser) parseHintArgs(c *Constraint) *Error {
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == RPAREN && len(c.Inputs) == 0 {
+			c.Literal += lit
+			return nil
+		}
+		if !isOperand(tok) {
+			return p.newError(lit, "expected a signal or a value")
+		}
+		c.Inputs = append(c.Inputs, lit)
+		c.Literal += lit
+		tok, lit = p.scanIgnoreWhitespace()
+		c.Literal += lit
+		if tok == RPAREN {
+			return nil
+		}
+		if tok != COMMA {
+			return p.newError(lit, "expected , or )")
+		}
+	}
+}
+
+// expectEndOfLine checks that the line has no more tokens
+func (p *Parser) expectEndOfLine() *Error {
+	tok, lit := p.scanIgnoreWhitespace()
 	if tok == EOF {
-		return nil, errors.New("eof in parseline")
+		p.unscan()
+		return nil
+	}
+	if tok != NEWLINE {
+		return p.newError(lit, "expected the end of the line")
+	}
+	return nil
+}
+
+// skipLine skips the rest of the current line, to continue parsing after an error
+func (p *Parser) skipLine() {
+	if p.buf.tok == NEWLINE || p.buf.tok == EOF {
+		return
+	}
+	for {
+		tok, _ := p.scan()
+		if tok == NEWLINE || tok == EOF {
+			return
+		}
 	}
-	return c, nil
 }
 
 func existInArray(arr []string, elem string) bool {
@@ -114,57 +359,155 @@ func addToArrayIfNotExist(arr []string, elem string) []string {
 	return arr
 }
 
-// Parse parses the lines and returns the compiled Circuit
+// Parse parses the lines and returns the compiled Circuit. If the code has
+// errors, the returned error is an ErrorList with all of them, and the
+// Circuit contains the lines without errors
 func (p *Parser) Parse() (*Circuit, error) {
 	circuit := &Circuit{}
 	circuit.Signals = append(circuit.Signals, "one")
-	nInputs := 0
+	var errs ErrorList
+	p.parseInto(circuit, &errs)
+	circuit.renameSSA()
+	if p.Optimize && len(errs) == 0 {
+		circuit.foldLinear()
+		circuit.eliminateDead()
+	}
+	for _, constraint := range circuit.Constraints {
+		circuit.addSignals(constraint)
+	}
+	circuit.checkInputs()
+	circuit.NVars = len(circuit.Signals)
+	circuit.NSignals = len(circuit.Signals)
+	return circuit, errs.Err()
+}
+
+// parseInto parses the lines, adding the constraints to the circuit and the errors to errs
+func (p *Parser) parseInto(circuit *Circuit, errs *ErrorList) {
 	for {
 		constraint, err := p.parseLine()
 		if err != nil {
+			*errs = append(*errs, err)
+			p.skipLine()
+			continue
+		}
+		if constraint == nil {
 			break
 		}
+		if constraint.Literal == "import" {
+			if err := p.parseImport(constraint, circuit, errs); err != nil {
+				*errs = append(*errs, err)
+			}
+			continue
+		}
 		if constraint.Literal == "func" {
+			if p.isImported {
+				*errs = append(*errs, &Error{Pos: constraint.Pos, Token: "func", Msg: "func declaration in imported file"})
+				continue
+			}
 			// one constraint for each input
 			for _, in := range constraint.Inputs {
 				newConstr := &Constraint{
 					Op:  "in",
 					Out: in,
+					Pos: constraint.Pos,
 				}
 				circuit.Constraints = append(circuit.Constraints, *newConstr)
-				nInputs++
 			}
 			circuit.Inputs = constraint.Inputs
 			continue
 		}
-		circuit.Constraints = append(circuit.Constraints, *constraint)
-		isVal, _ := isValue(constraint.V1)
-		if !isVal {
-			circuit.Signals = addToArrayIfNotExist(circuit.Signals, constraint.V1)
-		}
-		isVal, _ = isValue(constraint.V2)
-		if !isVal {
-			circuit.Signals = addToArrayIfNotExist(circuit.Signals, constraint.V2)
-		}
-		if constraint.Out == "out" {
-			// if Out is "out", put it after first value (one) and before the inputs
-			if !existInArray(circuit.Signals, constraint.Out) {
-				signalsCopy := copyArray(circuit.Signals)
-				var auxSignals []string
-				auxSignals = append(auxSignals, signalsCopy[0])
-				auxSignals = append(auxSignals, constraint.Out)
-				auxSignals = append(auxSignals, signalsCopy[1:]...)
-				circuit.Signals = auxSignals
-				circuit.PublicSignals = append(circuit.PublicSignals, constraint.Out)
-				circuit.NPublic++
+		if isComparison(constraint.Op) && !constraint.Hint && !constraint.Assert {
+			if isOrderingComparison(constraint.Op) && (p.ComparisonBits <= 0 || p.ComparisonBits > maxComparisonBits) {
+				errs.Add(constraint.Pos, constraint.Op, "the comparison bits must be between 1 and "+strconv.Itoa(maxComparisonBits))
+				continue
 			}
-		} else {
-			circuit.Signals = addToArrayIfNotExist(circuit.Signals, constraint.Out)
+			for _, c := range expandComparison(*constraint, p.ComparisonBits) {
+				c.Pos = constraint.Pos
+				circuit.Constraints = append(circuit.Constraints, c)
+			}
+			continue
 		}
+		circuit.Constraints = append(circuit.Constraints, *constraint)
+	}
+}
+
+// parseImport parses the imported file into the circuit. Each file is imported
+// only once, and the import cycles are errors
+func (p *Parser) parseImport(constraint *Constraint, circuit *Circuit, errs *ErrorList) *Error {
+	path := constraint.V1
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(p.path), path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return &Error{Pos: constraint.Pos, Token: constraint.V1, Msg: err.Error()}
+	}
+	for _, f := range p.stack {
+		if f == absPath {
+			return &Error{Pos: constraint.Pos, Token: constraint.V1, Msg: "import cycle"}
+		}
+	}
+	if p.imported[absPath] {
+		return nil
+	}
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return &Error{Pos: constraint.Pos, Token: constraint.V1, Msg: "can not read the imported file"}
+	}
+	p.imported[absPath] = true
+
+	imp := NewParser(bytes.NewReader(code))
+	imp.s.pos.File = path
+	imp.path = path
+	imp.isImported = true
+	imp.ComparisonBits = p.ComparisonBits
+	imp.stack = append(append([]string{}, p.stack...), absPath)
+	imp.imported = p.imported
+	imp.parseInto(circuit, errs)
+	return nil
+}
+
+// addSignals adds the new signals of the constraint to the Circuit
+func (circ *Circuit) addSignals(constraint Constraint) {
+	if constraint.Op == "in" {
+		circ.Signals = addToArrayIfNotExist(circ.Signals, constraint.Out)
+		return
+	}
+	if isSignal(constraint.V1) {
+		circ.Signals = addToArrayIfNotExist(circ.Signals, constraint.V1)
+	}
+	if isSignal(constraint.V2) {
+		circ.Signals = addToArrayIfNotExist(circ.Signals, constraint.V2)
+	}
+	for _, in := range constraint.Inputs {
+		if isSignal(in) {
+			circ.Signals = addToArrayIfNotExist(circ.Signals, in)
+		}
+	}
+	for _, lc := range []LinearCombination{constraint.Lin1, constraint.Lin2, constraint.LinOut} {
+		for _, term := range lc {
+			circ.Signals = addToArrayIfNotExist(circ.Signals, term.Signal)
+		}
+	}
+	if constraint.Assert {
+		// the out of an assertion is not a new signal
+		return
+	}
+	if constraint.Out == "out" {
+		// if Out is "out", put it after first value (one) and before the inputs
+		if !existInArray(circ.Signals, constraint.Out) {
+			signalsCopy := copyArray(circ.Signals)
+			var auxSignals []string
+			auxSignals = append(auxSignals, signalsCopy[0])
+			auxSignals = append(auxSignals, constraint.Out)
+			auxSignals = append(auxSignals, signalsCopy[1:]...)
+			circ.Signals = auxSignals
+			circ.PublicSignals = append(circ.PublicSignals, constraint.Out)
+			circ.NPublic++
+		}
+	} else {
+		circ.Signals = addToArrayIfNotExist(circ.Signals, constraint.Out)
 	}
-	circuit.NVars = len(circuit.Signals)
-	circuit.NSignals = len(circuit.Signals)
-	return circuit, nil
 }
 func copyArray(in []string) []string { // tmp
 	var out []string
@@ -0,0 +1,72 @@
+package circuitcompiler
+
+import (
+	"strconv"
+)
+
+// renameSSA renames the reassigned signals of the circuit code, so each
+// signal is assigned only once (Static Single Assignment form). The first
+// assignment of a signal keeps its name, and the next ones are renamed to
+// name.1, name.2, ..., except for the out signal, where the last assignment
+// keeps the name, as it is the public output of the circuit. The renamed
+// signals are mapped to their name in the code in Circuit.SourceNames
+func (circ *Circuit) renameSSA() {
+	total := make(map[string]int)
+	for _, constraint := range circ.Constraints {
+		if !constraint.Assert {
+			total[constraint.Out]++
+		}
+	}
+
+	current := make(map[string]string) // name in the code -> current name
+	count := make(map[string]int)      // assignments done of each name
+	rename := func(v string) string {
+		if r, ok := current[v]; ok {
+			return r
+		}
+		return v
+	}
+	for i := range circ.Constraints {
+		constraint := &circ.Constraints[i]
+		constraint.V1 = rename(constraint.V1)
+		constraint.V2 = rename(constraint.V2)
+		for j := range constraint.Inputs {
+			if constraint.Op != "in" {
+				constraint.Inputs[j] = rename(constraint.Inputs[j])
+			}
+		}
+		if constraint.Assert {
+			constraint.Out = rename(constraint.Out)
+			continue
+		}
+
+		name := constraint.Out
+		n := count[name]
+		count[name]++
+		newName := name
+		if name == "out" {
+			if count[name] < total[name] {
+				newName = name + "." + strconv.Itoa(n+1)
+			}
+		} else if n > 0 {
+			newName = name + "." + strconv.Itoa(n)
+		}
+		if newName != name {
+			if circ.SourceNames == nil {
+				circ.SourceNames = make(map[string]string)
+			}
+			circ.SourceNames[newName] = name
+		}
+		current[name] = newName
+		constraint.Out = newName
+	}
+}
+
+// SourceName returns the name in the circuit code of the given signal, which
+// can be different when the signal is reassigned in the code
+func (circ *Circuit) SourceName(signal string) string {
+	if name, ok := circ.SourceNames[signal]; ok {
+		return name
+	}
+	return signal
+}
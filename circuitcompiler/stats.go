@@ -0,0 +1,108 @@
+package circuitcompiler
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Sizes in bytes of the affine G1 and G2 points of the BN128 curve
+const (
+	G1PointSize = 64
+	G2PointSize = 128
+)
+
+// ProvingSystem describes the costs of a proving system, to estimate the size
+// of its proving key and the time to generate a proof of a circuit
+type ProvingSystem struct {
+	Name string
+	// KeyPoints returns the number of G1 and G2 points of the proving key
+	KeyPoints func(s Stats) (g1, g2 int)
+	// ProverMuls returns the number of G1 and G2 scalar multiplications to
+	// generate a proof
+	ProverMuls func(s Stats) (g1, g2 int)
+	// time of a scalar multiplication in G1 and G2
+	G1MulTime time.Duration
+	G2MulTime time.Duration
+}
+
+// Stats is the size of a compiled circuit
+type Stats struct {
+	Constraints   int // R1CS constraints
+	Signals       int // signals, including one
+	PublicOutputs int // public signals that are not inputs
+	PublicInputs  int
+	PrivateInputs int
+	Intermediate  int // signals that are not one, public nor inputs
+	NonZeroA      int // nonzero entries of the R1CS A matrix
+	NonZeroB      int
+	NonZeroC      int
+
+	ProvingSystem  string
+	ProvingKeySize int           // estimated size in bytes of the proving key
+	ProveTime      time.Duration // estimated time to generate a proof
+}
+
+func countNonZero(m [][]*big.Int) int {
+	n := 0
+	for _, row := range m {
+		for _, v := range row {
+			if !fqR.IsZero(fqR.Affine(v)) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Stats returns the size of the Circuit, and the estimated proving key size
+// and prove time with the given proving system
+func (circ *Circuit) Stats(system ProvingSystem) (Stats, error) {
+	a, b, c, err := circ.GenerateR1CS()
+	if err != nil {
+		return Stats{}, err
+	}
+	s := Stats{
+		Constraints:   len(a),
+		Signals:       len(circ.Signals),
+		NonZeroA:      countNonZero(a),
+		NonZeroB:      countNonZero(b),
+		NonZeroC:      countNonZero(c),
+		ProvingSystem: system.Name,
+	}
+	for _, signal := range circ.Signals[1:] {
+		public := existInArray(circ.PublicSignals, signal)
+		input := existInArray(circ.Inputs, signal)
+		switch {
+		case public && input:
+			s.PublicInputs++
+		case public:
+			s.PublicOutputs++
+		case input:
+			s.PrivateInputs++
+		default:
+			s.Intermediate++
+		}
+	}
+
+	g1, g2 := system.KeyPoints(s)
+	s.ProvingKeySize = g1*G1PointSize + g2*G2PointSize
+	g1, g2 = system.ProverMuls(s)
+	s.ProveTime = time.Duration(g1)*system.G1MulTime + time.Duration(g2)*system.G2MulTime
+	return s, nil
+}
+
+func (s Stats) String() string {
+	var r strings.Builder
+	fmt.Fprintf(&r, "constraints: %d\n", s.Constraints)
+	fmt.Fprintf(&r, "signals: %d\n", s.Signals)
+	fmt.Fprintf(&r, "public outputs: %d\n", s.PublicOutputs)
+	fmt.Fprintf(&r, "public inputs: %d\n", s.PublicInputs)
+	fmt.Fprintf(&r, "private inputs: %d\n", s.PrivateInputs)
+	fmt.Fprintf(&r, "intermediate signals: %d\n", s.Intermediate)
+	fmt.Fprintf(&r, "nonzero entries: A %d, B %d, C %d\n", s.NonZeroA, s.NonZeroB, s.NonZeroC)
+	fmt.Fprintf(&r, "%s proving key size: ~%d bytes\n", s.ProvingSystem, s.ProvingKeySize)
+	fmt.Fprintf(&r, "%s prove time: ~%s", s.ProvingSystem, s.ProveTime)
+	return r.String()
+}
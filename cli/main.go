@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -29,6 +28,29 @@ var commands = []cli.Command{
 		Aliases: []string{},
 		Usage:   "compile a circuit",
 		Action:  CompileCircuit,
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "no-optimize", Usage: "do not fold the linear constraints"},
+			cli.BoolFlag{Name: "circom", Usage: "also write the circuit.r1cs and witness.wtns circom files"},
+		},
+	},
+	{
+		Name:    "inspect",
+		Aliases: []string{},
+		Usage:   "print the statistics of a circuit",
+		Action:  InspectCircuit,
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "no-optimize", Usage: "do not fold the linear constraints"},
+		},
+	},
+	{
+		Name:    "graph",
+		Aliases: []string{},
+		Usage:   "export the dataflow graph of a circuit in the Graphviz DOT format",
+		Action:  GraphCircuit,
+		Flags: []cli.Flag{
+			cli.StringFlag{Name: "out", Value: "circuit.dot", Usage: "output file"},
+			cli.BoolFlag{Name: "no-optimize", Usage: "do not fold the linear constraints"},
+		},
 	},
 	{
 		Name:    "genproofs",
@@ -36,6 +58,21 @@ var commands = []cli.Command{
 		Usage:   "generate the snark proofs",
 		Action:  GenerateProofs,
 	},
+	{
+		Name:    "export-snarkjs",
+		Aliases: []string{},
+		Usage:   "export the verification key, proofs and public signals to the snarkjs json files",
+		Action:  ExportSnarkjs,
+	},
+	{
+		Name:    "export-verifier",
+		Aliases: []string{},
+		Usage:   "export the Solidity verifier contract, and print the calldata of the proofs",
+		Action:  ExportVerifier,
+		Flags: []cli.Flag{
+			cli.StringFlag{Name: "out", Value: "verifier.sol", Usage: "output file"},
+		},
+	},
 	{
 		Name:    "verify",
 		Aliases: []string{},
@@ -65,13 +102,16 @@ func CompileCircuit(context *cli.Context) error {
 	circuitPath := context.Args().Get(0)
 
 	// read circuit file
-	circuitFile, err := os.Open(circuitPath)
+	parser, err := circuitcompiler.NewFileParser(circuitPath)
 	panicErr(err)
+	parser.Optimize = !context.Bool("no-optimize")
 
 	// parse circuit code
-	parser := circuitcompiler.NewParser(bufio.NewReader(circuitFile))
 	circuit, err := parser.Parse()
 	panicErr(err)
+	for _, warning := range circuit.Warnings {
+		fmt.Println("warning:", warning)
+	}
 	fmt.Println("\ncircuit data:", circuit)
 
 	// read inputs file
@@ -90,12 +130,25 @@ func CompileCircuit(context *cli.Context) error {
 
 	// flat code to R1CS
 	fmt.Println("\ngenerating R1CS from flat code")
-	a, b, c := circuit.GenerateR1CS()
+	a, b, c, err := circuit.GenerateR1CS()
+	panicErr(err)
 	fmt.Println("\nR1CS:")
 	fmt.Println("a:", a)
 	fmt.Println("b:", b)
 	fmt.Println("c:", c)
 
+	if context.Bool("circom") {
+		r1csFile, err := os.Create("circuit.r1cs")
+		panicErr(err)
+		defer r1csFile.Close()
+		panicErr(circuit.WriteR1CS(r1csFile))
+		wtnsFile, err := os.Create("witness.wtns")
+		panicErr(err)
+		defer wtnsFile.Close()
+		panicErr(circuit.WriteWtns(wtnsFile, w))
+		fmt.Println("circom R1CS and witness written to circuit.r1cs and witness.wtns")
+	}
+
 	// R1CS to QAP
 	alphas, betas, gammas, zx := snark.Utils.PF.R1CSToQAP(a, b, c)
 	fmt.Println("qap")
@@ -173,6 +226,42 @@ func CompileCircuit(context *cli.Context) error {
 	return nil
 }
 
+func InspectCircuit(context *cli.Context) error {
+	circuitPath := context.Args().Get(0)
+
+	parser, err := circuitcompiler.NewFileParser(circuitPath)
+	panicErr(err)
+	parser.Optimize = !context.Bool("no-optimize")
+	circuit, err := parser.Parse()
+	panicErr(err)
+	for _, warning := range circuit.Warnings {
+		fmt.Println("warning:", warning)
+	}
+
+	stats, err := circuit.Stats(snark.PGHR13)
+	panicErr(err)
+	fmt.Println(stats)
+	return nil
+}
+
+func GraphCircuit(context *cli.Context) error {
+	circuitPath := context.Args().Get(0)
+
+	parser, err := circuitcompiler.NewFileParser(circuitPath)
+	panicErr(err)
+	parser.Optimize = !context.Bool("no-optimize")
+	circuit, err := parser.Parse()
+	panicErr(err)
+
+	dotFile, err := os.Create(context.String("out"))
+	panicErr(err)
+	defer dotFile.Close()
+	err = circuit.WriteDOT(dotFile)
+	panicErr(err)
+	fmt.Println("graph data written to", context.String("out"))
+	return nil
+}
+
 func GenerateProofs(context *cli.Context) error {
 	// open compiledcircuit.json
 	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
@@ -201,7 +290,8 @@ func GenerateProofs(context *cli.Context) error {
 	fmt.Println("\nwitness", w)
 
 	// flat code to R1CS
-	a, b, c := circuit.GenerateR1CS()
+	a, b, c, err := circuit.GenerateR1CS()
+	panicErr(err)
 	// R1CS to QAP
 	alphas, betas, gammas, zx := snark.Utils.PF.R1CSToQAP(a, b, c)
 	_, _, _, px := snark.Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
@@ -257,3 +347,73 @@ func VerifyProofs(context *cli.Context) error {
 	}
 	return nil
 }
+
+func storeJSON(path string, v interface{}) {
+	jsonData, err := json.Marshal(v)
+	panicErr(err)
+	err = ioutil.WriteFile(path, jsonData, 0644)
+	panicErr(err)
+	fmt.Println("data written to", path)
+}
+
+func ExportSnarkjs(context *cli.Context) error {
+	// open compiledcircuit.json
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+
+	// open trustedsetup.json
+	trustedsetupFile, err := ioutil.ReadFile("trustedsetup.json")
+	panicErr(err)
+	var trustedsetup snark.Setup
+	err = json.Unmarshal(trustedsetupFile, &trustedsetup)
+	panicErr(err)
+
+	// open proofs.json
+	proofsFile, err := ioutil.ReadFile("proofs.json")
+	panicErr(err)
+	var proof snark.Proof
+	err = json.Unmarshal(proofsFile, &proof)
+	panicErr(err)
+
+	storeJSON("verification_key.json", snark.NewSnarkjsVk(circuit, trustedsetup))
+	storeJSON("proof.json", snark.NewSnarkjsProof(proof))
+	storeJSON("public.json", snark.SnarkjsPublic(proof.PublicSignals))
+	return nil
+}
+
+func ExportVerifier(context *cli.Context) error {
+	// open compiledcircuit.json
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+
+	// open trustedsetup.json
+	trustedsetupFile, err := ioutil.ReadFile("trustedsetup.json")
+	panicErr(err)
+	var trustedsetup snark.Setup
+	err = json.Unmarshal(trustedsetupFile, &trustedsetup)
+	panicErr(err)
+
+	verifierFile, err := os.Create(context.String("out"))
+	panicErr(err)
+	defer verifierFile.Close()
+	panicErr(snark.WriteSolidityVerifier(verifierFile, circuit, trustedsetup))
+	fmt.Println("Solidity verifier written to", verifierFile.Name())
+
+	// print the calldata of proofs.json, if any
+	proofsFile, err := ioutil.ReadFile("proofs.json")
+	if err != nil {
+		return nil
+	}
+	var proof snark.Proof
+	err = json.Unmarshal(proofsFile, &proof)
+	panicErr(err)
+	fmt.Println("\nverifyProof calldata:")
+	fmt.Println(snark.NewVerifierCalldata(proof))
+	return nil
+}
@@ -0,0 +1,84 @@
+package fields
+
+import "math/big"
+
+// Field is the set of operations of a finite field over elements of type T,
+// implemented by Fq (over *big.Int), FqMont (over Element), and the
+// extension fields Fq2, Fq6 and Fq12, so the algorithms over a Field work in
+// any of them
+type Field[T any] interface {
+	Zero() T
+	One() T
+	Add(a, b T) T
+	Double(a T) T
+	Sub(a, b T) T
+	Neg(a T) T
+	Mul(a, b T) T
+	MulScalar(base T, e *big.Int) T
+	Inverse(a T) T
+	Div(a, b T) T
+	Square(a T) T
+	Exp(base T, e *big.Int) T
+	IsZero(a T) bool
+	Copy(a T) T
+	Affine(a T) T
+	Equal(a, b T) bool
+}
+
+var (
+	_ Field[*big.Int]          = Fq{}
+	_ Field[Element]           = FqMont{}
+	_ Field[[2]*big.Int]       = Fq2{}
+	_ Field[[3][2]*big.Int]    = Fq6{}
+	_ Field[[2][3][2]*big.Int] = Fq12{}
+)
+
+// Exp returns base^e in the Field, by square and multiply
+func Exp[T any](f Field[T], base T, e *big.Int) T {
+	res := f.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = f.Square(res)
+		if e.Bit(i) == 1 {
+			res = f.Mul(res, base)
+		}
+	}
+	return res
+}
+
+// MulScalar returns base added e times in the Field (e >= 0), by double and
+// add
+func MulScalar[T any](f Field[T], base T, e *big.Int) T {
+	res := f.Zero()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = f.Double(res)
+		if e.Bit(i) == 1 {
+			res = f.Add(res, base)
+		}
+	}
+	return res
+}
+
+// BatchInverse returns the inverses of the elements with one Inverse, with the
+// Montgomery trick. The inverse of zero is zero
+func BatchInverse[T any](f Field[T], a []T) []T {
+	// prod[i] is the product of the nonzero elements before i
+	prod := make([]T, len(a))
+	acc := f.One()
+	for i := range a {
+		prod[i] = acc
+		if !f.IsZero(a[i]) {
+			acc = f.Mul(acc, a[i])
+		}
+	}
+	inv := f.Inverse(acc)
+	res := make([]T, len(a))
+	for i := len(a) - 1; i >= 0; i-- {
+		if f.IsZero(a[i]) {
+			res[i] = f.Zero()
+			continue
+		}
+		res[i] = f.Mul(inv, prod[i])
+		inv = f.Mul(inv, a[i])
+	}
+	return res
+}
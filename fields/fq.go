@@ -90,6 +90,12 @@ func (fq Fq) Inverse(a *big.Int) *big.Int {
 	// return t
 }
 
+// BatchInverse returns the inverses of the elements with only one Inverse (the
+// inverse of zero is zero)
+func (fq Fq) BatchInverse(a []*big.Int) []*big.Int {
+	return BatchInverse[*big.Int](fq, a)
+}
+
 // Div performs the division over the finite field
 func (fq Fq) Div(a, b *big.Int) *big.Int {
 	d := fq.Mul(a, fq.Inverse(b))
@@ -104,18 +110,7 @@ func (fq Fq) Square(a *big.Int) *big.Int {
 
 // Exp performs the exponential over Fq
 func (fq Fq) Exp(base *big.Int, e *big.Int) *big.Int {
-	res := fq.One()
-	rem := fq.Copy(e)
-	exp := base
-
-	for !bytes.Equal(rem.Bytes(), big.NewInt(int64(0)).Bytes()) {
-		if BigIsOdd(rem) {
-			res = fq.Mul(res, exp)
-		}
-		exp = fq.Square(exp)
-		rem = new(big.Int).Rsh(rem, 1)
-	}
-	return res
+	return Exp[*big.Int](fq, base, e)
 }
 
 func (fq Fq) Rand() (*big.Int, error) {
@@ -170,3 +165,65 @@ func (fq Fq) Equal(a, b *big.Int) bool {
 	bAff := fq.Affine(b)
 	return bytes.Equal(aAff.Bytes(), bAff.Bytes())
 }
+
+// Legendre returns the Legendre symbol of a: 1 if a is a nonzero square in
+// the Fq, -1 if it is not a square, and 0 if it is zero
+func (fq Fq) Legendre(a *big.Int) int {
+	if fq.IsZero(fq.Affine(a)) {
+		return 0
+	}
+	e := new(big.Int).Rsh(new(big.Int).Sub(fq.Q, big.NewInt(int64(1))), 1)
+	if fq.Equal(fq.Exp(a, e), fq.One()) {
+		return 1
+	}
+	return -1
+}
+
+// Sqrt returns a square root of a, and false if a is not a square. It uses
+// a^((Q+1)/4) when Q = 3 mod 4, and the Tonelli-Shanks algorithm otherwise
+func (fq Fq) Sqrt(a *big.Int) (*big.Int, bool) {
+	a = fq.Affine(a)
+	switch fq.Legendre(a) {
+	case 0:
+		return fq.Zero(), true
+	case -1:
+		return nil, false
+	}
+	one := big.NewInt(int64(1))
+	if fq.Q.Bit(1) == 1 {
+		e := new(big.Int).Rsh(new(big.Int).Add(fq.Q, one), 2)
+		return fq.Exp(a, e), true
+	}
+
+	// Q-1 = s * 2^m, with s odd
+	s := new(big.Int).Sub(fq.Q, one)
+	m := 0
+	for s.Bit(0) == 0 {
+		s.Rsh(s, 1)
+		m++
+	}
+	// z is a non-square
+	z := big.NewInt(int64(2))
+	for fq.Legendre(z) != -1 {
+		z = new(big.Int).Add(z, one)
+	}
+	c := fq.Exp(z, s)
+	t := fq.Exp(a, s)
+	r := fq.Exp(a, new(big.Int).Rsh(new(big.Int).Add(s, one), 1))
+	for !fq.Equal(t, fq.One()) {
+		// the lowest i with t^(2^i) = 1
+		i := 0
+		for t2i := t; !fq.Equal(t2i, fq.One()); t2i = fq.Square(t2i) {
+			i++
+		}
+		b := c
+		for j := 0; j < m-i-1; j++ {
+			b = fq.Square(b)
+		}
+		m = i
+		c = fq.Square(b)
+		t = fq.Mul(t, c)
+		r = fq.Mul(r, b)
+	}
+	return r, true
+}
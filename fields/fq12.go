@@ -12,15 +12,19 @@ type Fq12 struct {
 	F          Fq6
 	Fq2        Fq2
 	NonResidue [2]*big.Int
+
+	// frobeniusC1[i] = NonResidue^((Q^i-1)/6)
+	frobeniusC1 [12][2]*big.Int
 }
 
 // NewFq12 generates a new Fq12
 func NewFq12(f Fq6, fq2 Fq2, nonResidue [2]*big.Int) Fq12 {
 	fq12 := Fq12{
-		f,
-		fq2,
-		nonResidue,
+		F:          f,
+		Fq2:        fq2,
+		NonResidue: nonResidue,
 	}
+	copy(fq12.frobeniusC1[:], frobeniusCoeffs(fq2, nonResidue, 6, 12))
 	return fq12
 }
 
@@ -83,22 +87,9 @@ func (fq12 Fq12) Mul(a, b [2][3][2]*big.Int) [2][3][2]*big.Int {
 	}
 }
 
+// MulScalar performs a scalar multiplication on the Fq12
 func (fq12 Fq12) MulScalar(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
-	// for more possible implementations see g2.go file, at the function g2.MulScalar()
-
-	res := fq12.Zero()
-	rem := e
-	exp := base
-
-	for !bytes.Equal(rem.Bytes(), big.NewInt(int64(0)).Bytes()) {
-		// if rem % 2 == 1
-		if bytes.Equal(new(big.Int).Rem(rem, big.NewInt(int64(2))).Bytes(), big.NewInt(int64(1)).Bytes()) {
-			res = fq12.Add(res, exp)
-		}
-		exp = fq12.Double(exp)
-		rem = rem.Rsh(rem, 1) // rem = rem >> 1
-	}
-	return res
+	return MulScalar[[2][3][2]*big.Int](fq12, base, e)
 }
 
 // Inverse returns the inverse on the Fq12
@@ -142,24 +133,85 @@ func BigIsOdd(n *big.Int) bool {
 	return bytes.Equal(and.Bytes(), big.NewInt(int64(1)).Bytes())
 }
 
+// Exp performs the exponential over the Fq12
 func (fq12 Fq12) Exp(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
-	// TODO fix bottleneck
+	return Exp[[2][3][2]*big.Int](fq12, base, e)
+}
 
-	res := fq12.One()
-	rem := fq12.Fq2.F.Copy(e)
-	exp := base
+// Frobenius returns a^(Q^power), with w^(Q^power) = NonResidue^((Q^power-1)/6) * w
+// for w^2 = v
+func (fq12 Fq12) Frobenius(a [2][3][2]*big.Int, power int) [2][3][2]*big.Int {
+	c := fq12.frobeniusC1[power%12]
+	b := fq12.F.Frobenius(a[1], power)
+	return [2][3][2]*big.Int{
+		fq12.F.Frobenius(a[0], power),
+		{
+			fq12.Fq2.Mul(b[0], c),
+			fq12.Fq2.Mul(b[1], c),
+			fq12.Fq2.Mul(b[2], c),
+		},
+	}
+}
 
-	// before := time.Now()
-	for !bytes.Equal(rem.Bytes(), big.NewInt(int64(0)).Bytes()) {
-		if BigIsOdd(rem) {
-			res = fq12.Mul(res, exp)
+// Conjugate returns a^(Q^6), which is the inverse for the elements of the
+// cyclotomic subgroup (the ones with a^(Q^6+1) = 1)
+func (fq12 Fq12) Conjugate(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	return [2][3][2]*big.Int{
+		fq12.F.Copy(a[0]),
+		fq12.F.Neg(a[1]),
+	}
+}
+
+// CyclotomicSquare performs a square operation on an element of the cyclotomic
+// subgroup (a^(Q^4-Q^2+1) = 1, as the result of the easy part of the final
+// exponentiation), with the Granger-Scott squaring over Fq4 = Fq2[w^3]
+// https://eprint.iacr.org/2009/565.pdf
+func (fq12 Fq12) CyclotomicSquare(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	f := fq12.Fq2
+	// the three Fq4 elements are (a[0][0], a[1][1]), (a[1][0], a[0][2]) and
+	// (a[0][1], a[1][2])
+	t0 := f.Square(a[1][1])
+	t1 := f.Square(a[0][0])
+	t6 := f.Sub(f.Sub(f.Square(f.Add(a[1][1], a[0][0])), t0), t1) // 2*a[1][1]*a[0][0]
+	t2 := f.Square(a[0][2])
+	t3 := f.Square(a[1][0])
+	t7 := f.Sub(f.Sub(f.Square(f.Add(a[0][2], a[1][0])), t2), t3) // 2*a[0][2]*a[1][0]
+	t4 := f.Square(a[1][2])
+	t5 := f.Square(a[0][1])
+	t8 := fq12.F.mulByNonResidue(f.Sub(f.Sub(f.Square(f.Add(a[1][2], a[0][1])), t4), t5))
+
+	t0 = f.Add(fq12.F.mulByNonResidue(t0), t1)
+	t2 = f.Add(fq12.F.mulByNonResidue(t2), t3)
+	t4 = f.Add(fq12.F.mulByNonResidue(t4), t5)
+
+	// 3*t - 2*a for the first Fq6 and 3*t + 2*a for the second one
+	return [2][3][2]*big.Int{
+		{
+			f.Add(f.Double(f.Sub(t0, a[0][0])), t0),
+			f.Add(f.Double(f.Sub(t2, a[0][1])), t2),
+			f.Add(f.Double(f.Sub(t4, a[0][2])), t4),
+		},
+		{
+			f.Add(f.Double(f.Add(t8, a[1][0])), t8),
+			f.Add(f.Double(f.Add(t6, a[1][1])), t6),
+			f.Add(f.Double(f.Add(t7, a[1][2])), t7),
+		},
+	}
+}
+
+// CyclotomicExp performs the exponential of an element of the cyclotomic
+// subgroup, with CyclotomicSquare
+func (fq12 Fq12) CyclotomicExp(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
+	res := fq12.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = fq12.CyclotomicSquare(res)
+		if e.Bit(i) == 1 {
+			res = fq12.Mul(res, base)
 		}
-		exp = fq12.Square(exp)
-		rem = new(big.Int).Rsh(rem, 1)
 	}
-	// fmt.Println("time elapsed:", time.Since(before))
 	return res
 }
+
 func (fq12 Fq12) Affine(a [2][3][2]*big.Int) [2][3][2]*big.Int {
 	return [2][3][2]*big.Int{
 		fq12.F.Affine(a[0]),
@@ -169,3 +221,14 @@ func (fq12 Fq12) Affine(a [2][3][2]*big.Int) [2][3][2]*big.Int {
 func (fq12 Fq12) Equal(a, b [2][3][2]*big.Int) bool {
 	return fq12.F.Equal(a[0], b[0]) && fq12.F.Equal(a[1], b[1])
 }
+
+func (fq12 Fq12) IsZero(a [2][3][2]*big.Int) bool {
+	return fq12.F.IsZero(a[0]) && fq12.F.IsZero(a[1])
+}
+
+func (fq12 Fq12) Copy(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	return [2][3][2]*big.Int{
+		fq12.F.Copy(a[0]),
+		fq12.F.Copy(a[1]),
+	}
+}
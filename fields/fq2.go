@@ -75,24 +75,9 @@ func (fq2 Fq2) Mul(a, b [2]*big.Int) [2]*big.Int {
 	}
 }
 
+// MulScalar performs a scalar multiplication on the Fq2
 func (fq2 Fq2) MulScalar(p [2]*big.Int, e *big.Int) [2]*big.Int {
-	// for more possible implementations see g2.go file, at the function g2.MulScalar()
-
-	q := fq2.Zero()
-	d := fq2.F.Copy(e)
-	r := p
-
-	foundone := false
-	for i := d.BitLen(); i >= 0; i-- {
-		if foundone {
-			q = fq2.Double(q)
-		}
-		if d.Bit(i) == 1 {
-			foundone = true
-			q = fq2.Add(q, r)
-		}
-	}
-	return q
+	return MulScalar[[2]*big.Int](fq2, p, e)
 }
 
 // Inverse returns the inverse on the Fq2
@@ -109,6 +94,29 @@ func (fq2 Fq2) Inverse(a [2]*big.Int) [2]*big.Int {
 	}
 }
 
+// Exp performs the exponential over the Fq2
+func (fq2 Fq2) Exp(base [2]*big.Int, e *big.Int) [2]*big.Int {
+	return Exp[[2]*big.Int](fq2, base, e)
+}
+
+// Frobenius returns a^(Q^power). As u^Q = -u for u^2 = NonResidue, it is the
+// conjugate for the odd powers
+func (fq2 Fq2) Frobenius(a [2]*big.Int, power int) [2]*big.Int {
+	if power%2 == 0 {
+		return fq2.Copy(a)
+	}
+	return [2]*big.Int{
+		fq2.F.Copy(a[0]),
+		fq2.F.Neg(a[1]),
+	}
+}
+
+// BatchInverse returns the inverses of the elements with only one Inverse (the
+// inverse of zero is zero)
+func (fq2 Fq2) BatchInverse(a [][2]*big.Int) [][2]*big.Int {
+	return BatchInverse[[2]*big.Int](fq2, a)
+}
+
 // Div performs a division on the Fq2
 func (fq2 Fq2) Div(a, b [2]*big.Int) [2]*big.Int {
 	return fq2.Mul(a, fq2.Inverse(b))
@@ -152,3 +160,39 @@ func (fq2 Fq2) Copy(a [2]*big.Int) [2]*big.Int {
 		fq2.F.Copy(a[1]),
 	}
 }
+
+// Sqrt returns a square root of a, and false if a is not a square. With
+// a = a0 + a1*u, u^2 = NonResidue, the root x0 + x1*u has x0^2 = (a0 + n)/2
+// (or (a0 - n)/2), where n is the square root of the norm a0^2 - NonResidue*a1^2,
+// and x1 = a1 / (2*x0)
+func (fq2 Fq2) Sqrt(a [2]*big.Int) ([2]*big.Int, bool) {
+	f := fq2.F
+	if f.IsZero(f.Affine(a[1])) {
+		if x0, ok := f.Sqrt(a[0]); ok {
+			return [2]*big.Int{x0, f.Zero()}, true
+		}
+		// a0 = x1^2 * NonResidue
+		x1, ok := f.Sqrt(f.Div(a[0], fq2.NonResidue))
+		if !ok {
+			return [2]*big.Int{}, false
+		}
+		return [2]*big.Int{f.Zero(), x1}, true
+	}
+	norm := f.Sub(f.Square(a[0]), fq2.mulByNonResidue(f.Square(a[1])))
+	n, ok := f.Sqrt(norm)
+	if !ok {
+		return [2]*big.Int{}, false
+	}
+	twoInv := f.Inverse(big.NewInt(int64(2)))
+	d := f.Mul(f.Add(a[0], n), twoInv)
+	x0, ok := f.Sqrt(d)
+	if !ok {
+		d = f.Mul(f.Sub(a[0], n), twoInv)
+		x0, ok = f.Sqrt(d)
+		if !ok {
+			return [2]*big.Int{}, false
+		}
+	}
+	x1 := f.Div(a[1], f.Double(x0))
+	return [2]*big.Int{x0, x1}, true
+}
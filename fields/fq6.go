@@ -1,7 +1,6 @@
 package fields
 
 import (
-	"bytes"
 	"math/big"
 )
 
@@ -9,17 +8,38 @@ import (
 type Fq6 struct {
 	F          Fq2
 	NonResidue [2]*big.Int
+
+	// frobeniusC1[i] = NonResidue^((Q^i-1)/3), frobeniusC2[i] = frobeniusC1[i]^2
+	frobeniusC1 [6][2]*big.Int
+	frobeniusC2 [6][2]*big.Int
 }
 
 // NewFq6 generates a new Fq6
 func NewFq6(f Fq2, nonResidue [2]*big.Int) Fq6 {
 	fq6 := Fq6{
-		f,
-		nonResidue,
+		F:          f,
+		NonResidue: nonResidue,
+	}
+	c1 := frobeniusCoeffs(f, nonResidue, 3, 6)
+	for i := range c1 {
+		fq6.frobeniusC1[i] = c1[i]
+		fq6.frobeniusC2[i] = f.Square(c1[i])
 	}
 	return fq6
 }
 
+// frobeniusCoeffs returns nonResidue^((Q^i-1)/d) for i in [0, n), as the
+// products of the Frobenius of nonResidue^((Q-1)/d)
+func frobeniusCoeffs(fq2 Fq2, nonResidue [2]*big.Int, d int64, n int) [][2]*big.Int {
+	e := new(big.Int).Div(new(big.Int).Sub(fq2.F.Q, big.NewInt(int64(1))), big.NewInt(d))
+	c := fq2.Exp(nonResidue, e)
+	coeffs := [][2]*big.Int{fq2.One()}
+	for i := 1; i < n; i++ {
+		coeffs = append(coeffs, fq2.Mul(coeffs[i-1], fq2.Frobenius(c, i-1)))
+	}
+	return coeffs
+}
+
 // Zero returns a Zero value on the Fq6
 func (fq6 Fq6) Zero() [3][2]*big.Int {
 	return [3][2]*big.Int{fq6.F.Zero(), fq6.F.Zero(), fq6.F.Zero()}
@@ -94,22 +114,9 @@ func (fq6 Fq6) Mul(a, b [3][2]*big.Int) [3][2]*big.Int {
 	}
 }
 
+// MulScalar performs a scalar multiplication on the Fq6
 func (fq6 Fq6) MulScalar(base [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
-	// for more possible implementations see g2.go file, at the function g2.MulScalar()
-
-	res := fq6.Zero()
-	rem := e
-	exp := base
-
-	for !bytes.Equal(rem.Bytes(), big.NewInt(int64(0)).Bytes()) {
-		// if rem % 2 == 1
-		if bytes.Equal(new(big.Int).Rem(rem, big.NewInt(int64(2))).Bytes(), big.NewInt(int64(1)).Bytes()) {
-			res = fq6.Add(res, exp)
-		}
-		exp = fq6.Double(exp)
-		rem = rem.Rsh(rem, 1) // rem = rem >> 1
-	}
-	return res
+	return MulScalar[[3][2]*big.Int](fq6, base, e)
 }
 
 // Inverse returns the inverse on the Fq6
@@ -172,6 +179,26 @@ func (fq6 Fq6) Square(a [3][2]*big.Int) [3][2]*big.Int {
 	}
 }
 
+// Exp performs the exponential over the Fq6
+func (fq6 Fq6) Exp(base [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	return Exp[[3][2]*big.Int](fq6, base, e)
+}
+
+// Frobenius returns a^(Q^power), with v^(Q^power) = NonResidue^((Q^power-1)/3) * v
+// for v^3 = NonResidue
+func (fq6 Fq6) Frobenius(a [3][2]*big.Int, power int) [3][2]*big.Int {
+	i := power % 6
+	return [3][2]*big.Int{
+		fq6.F.Frobenius(a[0], power),
+		fq6.F.Mul(fq6.F.Frobenius(a[1], power), fq6.frobeniusC1[i]),
+		fq6.F.Mul(fq6.F.Frobenius(a[2], power), fq6.frobeniusC2[i]),
+	}
+}
+
+func (fq6 Fq6) IsZero(a [3][2]*big.Int) bool {
+	return fq6.F.IsZero(a[0]) && fq6.F.IsZero(a[1]) && fq6.F.IsZero(a[2])
+}
+
 func (fq6 Fq6) Affine(a [3][2]*big.Int) [3][2]*big.Int {
 	return [3][2]*big.Int{
 		fq6.F.Affine(a[0]),
@@ -0,0 +1,222 @@
+package fields
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// Element is an element of a FqMont, as four 64-bit little endian limbs in
+// Montgomery form (a * 2^256 mod Q)
+type Element [4]uint64
+
+// FqMont is the Z field over a modulus Q of up to 255 bits, with the elements
+// in Montgomery form of fixed size, so the operations do not allocate. It has
+// the same methods as Fq, over Element instead of *big.Int
+type FqMont struct {
+	Q    *big.Int
+	q    Element
+	qInv uint64  // -Q^-1 mod 2^64
+	r2   Element // 2^512 mod Q
+	one  Element // 2^256 mod Q
+}
+
+// NewFqMont generates a new FqMont, for an odd modulus q < 2^255
+func NewFqMont(q *big.Int) (FqMont, error) {
+	if q.Sign() != 1 || q.Bit(0) == 0 || q.BitLen() > 255 {
+		return FqMont{}, errors.New("the modulus must be odd and lower than 2^255")
+	}
+	f := FqMont{Q: q}
+	f.q = bigToLimbs(q)
+
+	// Newton iteration for q^-1 mod 2^64
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.q[0]*inv
+	}
+	f.qInv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(int64(1)), 256)
+	f.one = bigToLimbs(new(big.Int).Mod(r, q))
+	f.r2 = bigToLimbs(new(big.Int).Mod(new(big.Int).Mul(r, r), q))
+	return f, nil
+}
+
+func bigToLimbs(a *big.Int) Element {
+	var e Element
+	b := a.Bytes()
+	for i := 0; i < len(b) && i < 32; i++ {
+		e[i/8] |= uint64(b[len(b)-1-i]) << (8 * uint(i%8))
+	}
+	return e
+}
+
+func limbsToBig(e Element) *big.Int {
+	b := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		b[31-i] = byte(e[i/8] >> (8 * uint(i%8)))
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// SetBig returns the Element of the integer a (reduced modulo Q)
+func (fq FqMont) SetBig(a *big.Int) Element {
+	aq := new(big.Int).Mod(a, fq.Q)
+	return fq.Mul(bigToLimbs(aq), fq.r2)
+}
+
+// Big returns the integer value of the Element, in [0, Q)
+func (fq FqMont) Big(a Element) *big.Int {
+	return limbsToBig(fq.Mul(a, Element{1}))
+}
+
+// Zero returns a Zero value on the FqMont
+func (fq FqMont) Zero() Element {
+	return Element{}
+}
+
+// One returns a One value on the FqMont
+func (fq FqMont) One() Element {
+	return fq.one
+}
+
+// reduce subtracts q from the value of 5 limbs (t, hi) if it is not lower
+// than q
+func (fq FqMont) reduce(t Element, hi uint64) Element {
+	var s Element
+	var borrow uint64
+	s[0], borrow = bits.Sub64(t[0], fq.q[0], 0)
+	s[1], borrow = bits.Sub64(t[1], fq.q[1], borrow)
+	s[2], borrow = bits.Sub64(t[2], fq.q[2], borrow)
+	s[3], borrow = bits.Sub64(t[3], fq.q[3], borrow)
+	_, borrow = bits.Sub64(hi, 0, borrow)
+	if borrow != 0 {
+		return t
+	}
+	return s
+}
+
+// Add performs an addition on the FqMont
+func (fq FqMont) Add(a, b Element) Element {
+	var t Element
+	var carry uint64
+	t[0], carry = bits.Add64(a[0], b[0], 0)
+	t[1], carry = bits.Add64(a[1], b[1], carry)
+	t[2], carry = bits.Add64(a[2], b[2], carry)
+	t[3], carry = bits.Add64(a[3], b[3], carry)
+	return fq.reduce(t, carry)
+}
+
+// Double performs a doubling on the FqMont
+func (fq FqMont) Double(a Element) Element {
+	return fq.Add(a, a)
+}
+
+// Sub performs a subtraction on the FqMont
+func (fq FqMont) Sub(a, b Element) Element {
+	var t Element
+	var borrow uint64
+	t[0], borrow = bits.Sub64(a[0], b[0], 0)
+	t[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	t[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	t[3], borrow = bits.Sub64(a[3], b[3], borrow)
+	if borrow != 0 {
+		var carry uint64
+		t[0], carry = bits.Add64(t[0], fq.q[0], 0)
+		t[1], carry = bits.Add64(t[1], fq.q[1], carry)
+		t[2], carry = bits.Add64(t[2], fq.q[2], carry)
+		t[3], _ = bits.Add64(t[3], fq.q[3], carry)
+	}
+	return t
+}
+
+// Neg performs a negation on the FqMont
+func (fq FqMont) Neg(a Element) Element {
+	return fq.Sub(Element{}, a)
+}
+
+// madd returns the 128 bits a*b + c + d as (hi, lo)
+func madd(a, b, c, d uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// Mul performs a multiplication on the FqMont, with the CIOS Montgomery
+// multiplication
+func (fq FqMont) Mul(a, b Element) Element {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			c, t[j] = madd(a[j], b[i], t[j], c)
+		}
+		var carry uint64
+		t[4], carry = bits.Add64(t[4], c, 0)
+		t[5] = carry
+
+		m := t[0] * fq.qInv
+		c, _ = madd(m, fq.q[0], t[0], 0)
+		for j := 1; j < 4; j++ {
+			c, t[j-1] = madd(m, fq.q[j], t[j], c)
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + carry
+	}
+	return fq.reduce(Element{t[0], t[1], t[2], t[3]}, t[4])
+}
+
+// MulScalar multiplies the Element by the integer e
+func (fq FqMont) MulScalar(base Element, e *big.Int) Element {
+	return fq.Mul(base, fq.SetBig(e))
+}
+
+// Square performs a square operation on the FqMont
+func (fq FqMont) Square(a Element) Element {
+	return fq.Mul(a, a)
+}
+
+// Exp performs the exponential over FqMont
+func (fq FqMont) Exp(base Element, e *big.Int) Element {
+	return Exp[Element](fq, base, e)
+}
+
+// Inverse returns the inverse on the FqMont, as a^(Q-2) (zero for zero)
+func (fq FqMont) Inverse(a Element) Element {
+	return fq.Exp(a, new(big.Int).Sub(fq.Q, big.NewInt(int64(2))))
+}
+
+// Div performs the division over the finite field
+func (fq FqMont) Div(a, b Element) Element {
+	return fq.Mul(a, fq.Inverse(b))
+}
+
+func (fq FqMont) Rand() (Element, error) {
+	r, err := rand.Int(rand.Reader, fq.Q)
+	if err != nil {
+		return Element{}, err
+	}
+	return fq.SetBig(r), nil
+}
+
+func (fq FqMont) IsZero(a Element) bool {
+	return a == Element{}
+}
+
+func (fq FqMont) Copy(a Element) Element {
+	return a
+}
+
+// Affine returns the Element, as the elements are always reduced
+func (fq FqMont) Affine(a Element) Element {
+	return a
+}
+
+func (fq FqMont) Equal(a, b Element) bool {
+	return a == b
+}
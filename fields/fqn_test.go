@@ -42,6 +42,9 @@ func TestFq1(t *testing.T) {
 
 	res = fq1.Square(iToBig(5))
 	assert.Equal(t, iToBig(4), res)
+
+	inv := fq1.BatchInverse([]*big.Int{iToBig(4), iToBig(0), iToBig(3)})
+	assert.Equal(t, []*big.Int{iToBig(2), iToBig(0), iToBig(5)}, inv)
 }
 
 func TestFq2(t *testing.T) {
@@ -51,7 +54,7 @@ func TestFq2(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, nonResidueFq2.String(), nonResidueFq2str)
 
-	fq2 := Fq2{fq1, nonResidueFq2}
+	fq2 := NewFq2(fq1, nonResidueFq2)
 
 	res := fq2.Add(iiToBig(4, 4), iiToBig(3, 4))
 	assert.Equal(t, iiToBig(0, 1), fq2.Affine(res))
@@ -81,6 +84,10 @@ func TestFq2(t *testing.T) {
 	assert.Equal(t, iiToBig(5, 2), fq2.Affine(res))
 	res2 = fq2.Mul(iiToBig(3, 5), iiToBig(3, 5))
 	assert.Equal(t, fq2.Affine(res), fq2.Affine(res2))
+
+	inv := fq2.BatchInverse([][2]*big.Int{iiToBig(4, 4), iiToBig(3, 5)})
+	assert.Equal(t, iiToBig(1, 6), fq2.Affine(inv[0]))
+	assert.Equal(t, fq2.Affine(fq2.Inverse(iiToBig(3, 5))), fq2.Affine(inv[1]))
 }
 
 func TestFq6(t *testing.T) {
@@ -93,8 +100,8 @@ func TestFq6(t *testing.T) {
 	assert.True(t, ok)
 	nonResidueFq6 := iiToBig(9, 1)
 
-	fq2 := Fq2{fq1, nonResidueFq2}
-	fq6 := Fq6{fq2, nonResidueFq6}
+	fq2 := NewFq2(fq1, nonResidueFq2)
+	fq6 := NewFq6(fq2, nonResidueFq6)
 
 	a := [3][2]*big.Int{
 		iiToBig(1, 2),
@@ -118,9 +125,9 @@ func TestFq12(t *testing.T) {
 	assert.True(t, ok)
 	nonResidueFq6 := iiToBig(9, 1)
 
-	fq2 := Fq2{fq1, nonResidueFq2}
-	fq6 := Fq6{fq2, nonResidueFq6}
-	fq12 := Fq12{fq6, fq2, nonResidueFq6}
+	fq2 := NewFq2(fq1, nonResidueFq2)
+	fq6 := NewFq6(fq2, nonResidueFq6)
+	fq12 := NewFq12(fq6, fq2, nonResidueFq6)
 
 	a := [2][3][2]*big.Int{
 		{
@@ -167,3 +174,224 @@ func TestFq12(t *testing.T) {
 	divRes := fq12.Div(mulRes, b)
 	assert.Equal(t, fq12.Affine(a), fq12.Affine(divRes))
 }
+
+func TestFqMont(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+
+	for _, modulus := range []*big.Int{q, r} {
+		fq := NewFq(modulus)
+		fm, err := NewFqMont(modulus)
+		assert.Nil(t, err)
+
+		assert.Equal(t, iToBig(0).String(), fm.Big(fm.Zero()).String())
+		assert.Equal(t, iToBig(1).String(), fm.Big(fm.One()).String())
+		assert.Equal(t, iToBig(0).String(), fm.Big(fm.SetBig(modulus)).String())
+		assert.Equal(t, new(big.Int).Sub(modulus, iToBig(1)).String(), fm.Big(fm.SetBig(iToBig(-1))).String())
+
+		values := []*big.Int{iToBig(0), iToBig(1), iToBig(2), new(big.Int).Sub(modulus, iToBig(1))}
+		for i := 0; i < 50; i++ {
+			v, err := fq.Rand()
+			assert.Nil(t, err)
+			values = append(values, v)
+		}
+		for i, a := range values {
+			b := values[(i*7+3)%len(values)]
+			ma := fm.SetBig(a)
+			mb := fm.SetBig(b)
+			assert.Equal(t, a.String(), fm.Big(ma).String())
+			assert.Equal(t, fq.Affine(fq.Add(a, b)).String(), fm.Big(fm.Add(ma, mb)).String())
+			assert.Equal(t, fq.Affine(fq.Double(a)).String(), fm.Big(fm.Double(ma)).String())
+			assert.Equal(t, fq.Affine(fq.Sub(a, b)).String(), fm.Big(fm.Sub(ma, mb)).String())
+			assert.Equal(t, fq.Affine(fq.Neg(a)).String(), fm.Big(fm.Neg(ma)).String())
+			assert.Equal(t, fq.Affine(fq.Mul(a, b)).String(), fm.Big(fm.Mul(ma, mb)).String())
+			assert.Equal(t, fq.Affine(fq.Square(a)).String(), fm.Big(fm.Square(ma)).String())
+			assert.Equal(t, fq.Affine(fq.Exp(a, b)).String(), fm.Big(fm.Exp(ma, b)).String())
+			assert.Equal(t, fq.IsZero(a), fm.IsZero(ma))
+			if !fq.IsZero(b) {
+				assert.Equal(t, fq.Affine(fq.Inverse(b)).String(), fm.Big(fm.Inverse(mb)).String())
+				assert.Equal(t, fq.Affine(fq.Div(a, b)).String(), fm.Big(fm.Div(ma, mb)).String())
+			}
+		}
+	}
+
+	_, err := NewFqMont(iToBig(8))
+	assert.NotNil(t, err)
+}
+
+// expByMul computes base^e with e multiplications, to check the generic Exp
+func expByMul[T any](f Field[T], base T, e int) T {
+	res := f.One()
+	for i := 0; i < e; i++ {
+		res = f.Mul(res, base)
+	}
+	return res
+}
+
+func checkField[T any](t *testing.T, f Field[T], a, b T) {
+	for _, e := range []int{0, 1, 2, 7, 10} {
+		assert.True(t, f.Equal(expByMul(f, a, e), f.Exp(a, iToBig(e))))
+		sum := f.Zero()
+		for i := 0; i < e; i++ {
+			sum = f.Add(sum, a)
+		}
+		assert.True(t, f.Equal(sum, f.MulScalar(a, iToBig(e))))
+	}
+	// MulScalar does not modify the scalar
+	e := iToBig(10)
+	f.MulScalar(a, e)
+	assert.Equal(t, iToBig(10), e)
+
+	inv := BatchInverse(f, []T{a, f.Zero(), b, f.Mul(a, b)})
+	assert.True(t, f.Equal(f.Inverse(a), inv[0]))
+	assert.True(t, f.IsZero(inv[1]))
+	assert.True(t, f.Equal(f.Inverse(b), inv[2]))
+	assert.True(t, f.Equal(f.One(), f.Mul(f.Mul(a, b), inv[3])))
+}
+
+func TestFieldGeneric(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	fm, err := NewFqMont(q)
+	assert.Nil(t, err)
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	nonResidueFq6 := iiToBig(9, 1)
+	fq2 := NewFq2(fq1, nonResidueFq2)
+	fq6 := NewFq6(fq2, nonResidueFq6)
+	fq12 := NewFq12(fq6, fq2, nonResidueFq6)
+
+	checkField[*big.Int](t, fq1, iToBig(5), iToBig(12))
+	checkField[Element](t, fm, fm.SetBig(iToBig(5)), fm.SetBig(iToBig(12)))
+	checkField[[2]*big.Int](t, fq2, iiToBig(1, 2), iiToBig(3, 4))
+	a6 := [3][2]*big.Int{iiToBig(1, 2), iiToBig(3, 4), iiToBig(5, 6)}
+	b6 := [3][2]*big.Int{iiToBig(12, 11), iiToBig(10, 9), iiToBig(8, 7)}
+	checkField[[3][2]*big.Int](t, fq6, a6, b6)
+	checkField[[2][3][2]*big.Int](t, fq12, [2][3][2]*big.Int{a6, b6}, [2][3][2]*big.Int{b6, a6})
+}
+
+func TestSqrt(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+
+	// q = 3 mod 4, and r = 1 mod 4 (Tonelli-Shanks)
+	for _, modulus := range []*big.Int{q, r, iToBig(7), iToBig(13)} {
+		fq := NewFq(modulus)
+		assert.Equal(t, 0, fq.Legendre(fq.Zero()))
+		root, ok := fq.Sqrt(fq.Zero())
+		assert.True(t, ok)
+		assert.True(t, fq.IsZero(root))
+
+		var values []*big.Int
+		for i := 1; i < 20; i++ {
+			if modulus.BitLen() > 8 {
+				a, err := fq.Rand()
+				assert.Nil(t, err)
+				values = append(values, a)
+			} else if int64(i) < modulus.Int64() {
+				values = append(values, iToBig(i))
+			}
+		}
+		nonSquares := 0
+		for _, a := range values {
+			if fq.IsZero(a) {
+				continue
+			}
+			a2 := fq.Square(a)
+			assert.Equal(t, 1, fq.Legendre(a2))
+			root, ok := fq.Sqrt(a2)
+			assert.True(t, ok)
+			assert.True(t, fq.Equal(a2, fq.Square(root)))
+
+			if fq.Legendre(a) == -1 {
+				nonSquares++
+				_, ok := fq.Sqrt(a)
+				assert.False(t, ok)
+			}
+		}
+		assert.True(t, nonSquares > 0)
+	}
+
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	fq2 := NewFq2(fq1, nonResidueFq2)
+	nonSquares := 0
+	for i := 0; i < 20; i++ {
+		a0, err := fq1.Rand()
+		assert.Nil(t, err)
+		a1, err := fq1.Rand()
+		assert.Nil(t, err)
+		a := [2]*big.Int{a0, a1}
+		a2 := fq2.Square(a)
+		root, ok := fq2.Sqrt(a2)
+		assert.True(t, ok)
+		assert.True(t, fq2.Equal(a2, fq2.Square(root)))
+
+		// a is a square iff its norm is a square in Fq
+		norm := fq1.Add(fq1.Square(a0), fq1.Square(a1))
+		if fq1.Legendre(norm) == -1 {
+			nonSquares++
+			_, ok := fq2.Sqrt(a)
+			assert.False(t, ok)
+		}
+	}
+	assert.True(t, nonSquares > 0)
+
+	// elements of Fq
+	for _, a0 := range []*big.Int{iToBig(3), iToBig(4), fq1.Neg(iToBig(4))} {
+		a := [2]*big.Int{a0, fq1.Zero()}
+		root, ok := fq2.Sqrt(a)
+		assert.True(t, ok)
+		assert.True(t, fq2.Equal(a, fq2.Square(root)))
+	}
+}
+
+func TestFrobenius(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	nonResidueFq6 := iiToBig(9, 1)
+	fq2 := NewFq2(fq1, nonResidueFq2)
+	fq6 := NewFq6(fq2, nonResidueFq6)
+	fq12 := NewFq12(fq6, fq2, nonResidueFq6)
+
+	var c [12]*big.Int
+	for i := range c {
+		var err error
+		c[i], err = fq1.Rand()
+		assert.Nil(t, err)
+	}
+	a2 := [2]*big.Int{c[0], c[1]}
+	a6 := [3][2]*big.Int{{c[0], c[1]}, {c[2], c[3]}, {c[4], c[5]}}
+	a12 := [2][3][2]*big.Int{a6, {{c[6], c[7]}, {c[8], c[9]}, {c[10], c[11]}}}
+
+	// the Frobenius map is the exponentiation to q
+	assert.True(t, fq2.Equal(fq2.Exp(a2, q), fq2.Frobenius(a2, 1)))
+	assert.True(t, fq6.Equal(fq6.Exp(a6, q), fq6.Frobenius(a6, 1)))
+	assert.True(t, fq12.Equal(fq12.Exp(a12, q), fq12.Frobenius(a12, 1)))
+	f := a12
+	for i := 1; i <= 12; i++ {
+		f = fq12.Frobenius(f, 1)
+		assert.True(t, fq12.Equal(f, fq12.Frobenius(a12, i)))
+		assert.True(t, fq6.Equal(fq6.Frobenius(fq6.Frobenius(a6, i-1), 1), fq6.Frobenius(a6, i)))
+	}
+	assert.True(t, fq12.Equal(a12, f))
+	assert.True(t, fq12.Equal(fq12.Frobenius(a12, 6), fq12.Conjugate(a12)))
+
+	// element of the cyclotomic subgroup: a^((q^6-1)(q^2+1))
+	g := fq12.Mul(fq12.Conjugate(a12), fq12.Inverse(a12))
+	g = fq12.Mul(fq12.Frobenius(g, 2), g)
+	assert.True(t, fq12.Equal(fq12.One(), fq12.Mul(g, fq12.Conjugate(g))))
+	assert.True(t, fq12.Equal(fq12.Square(g), fq12.CyclotomicSquare(g)))
+	e, ok := new(big.Int).SetString("4965661367192848881", 10)
+	assert.True(t, ok)
+	assert.True(t, fq12.Equal(fq12.Exp(g, e), fq12.CyclotomicExp(g, e)))
+}
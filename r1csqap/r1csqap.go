@@ -2,7 +2,6 @@ package r1csqap
 
 import (
 	"bytes"
-	"fmt"
 	"math/big"
 
 	"github.com/arnaucube/go-snark/fields"
@@ -10,9 +9,13 @@ import (
 
 // Transpose transposes the *big.Int matrix
 func Transpose(matrix [][]*big.Int) [][]*big.Int {
-	var r [][]*big.Int
+	return transpose(matrix)
+}
+
+func transpose[T any](matrix [][]T) [][]T {
+	var r [][]T
 	for i := 0; i < len(matrix[0]); i++ {
-		var row []*big.Int
+		var row []T
 		for j := 0; j < len(matrix); j++ {
 			row = append(row, matrix[j][i])
 		}
@@ -42,43 +45,61 @@ func BigArraysEqual(a, b []*big.Int) bool {
 	return true
 }
 
-// PolynomialField is the Polynomial over a Finite Field where the polynomial operations are performed
-type PolynomialField struct {
-	F fields.Fq
+// Polynomials performs the polynomial operations over any Finite Field, being
+// the polynomials the arrays of their coefficients of type T, from the lowest
+// degree
+type Polynomials[T any] struct {
+	F fields.Field[T]
 }
 
-// NewPolynomialField creates a new PolynomialField with the given FiniteField
-func NewPolynomialField(f fields.Fq) PolynomialField {
-	return PolynomialField{
+// NewPolynomials creates a new Polynomials over the given Finite Field
+func NewPolynomials[T any](f fields.Field[T]) Polynomials[T] {
+	return Polynomials[T]{
 		f,
 	}
 }
 
+func (p Polynomials[T]) zeros(num int) []T {
+	var r []T
+	for i := 0; i < num; i++ {
+		r = append(r, p.F.Zero())
+	}
+	return r
+}
+
+// fromInt returns the integer n as an element of the Finite Field
+func (p Polynomials[T]) fromInt(n int) T {
+	if n < 0 {
+		return p.F.Neg(p.fromInt(-n))
+	}
+	return p.F.MulScalar(p.F.One(), big.NewInt(int64(n)))
+}
+
 // Mul multiplies two polinomials over the Finite Field
-func (pf PolynomialField) Mul(a, b []*big.Int) []*big.Int {
-	r := ArrayOfBigZeros(len(a) + len(b) - 1)
+func (p Polynomials[T]) Mul(a, b []T) []T {
+	r := p.zeros(len(a) + len(b) - 1)
 	for i := 0; i < len(a); i++ {
 		for j := 0; j < len(b); j++ {
-			r[i+j] = pf.F.Add(
+			r[i+j] = p.F.Add(
 				r[i+j],
-				pf.F.Mul(a[i], b[j]))
+				p.F.Mul(a[i], b[j]))
 		}
 	}
 	return r
 }
 
 // Div divides two polinomials over the Finite Field, returning the result and the remainder
-func (pf PolynomialField) Div(a, b []*big.Int) ([]*big.Int, []*big.Int) {
+func (p Polynomials[T]) Div(a, b []T) ([]T, []T) {
 	// https://en.wikipedia.org/wiki/Division_algorithm
-	r := ArrayOfBigZeros(len(a) - len(b) + 1)
+	r := p.zeros(len(a) - len(b) + 1)
 	rem := a
 	for len(rem) >= len(b) {
-		l := pf.F.Div(rem[len(rem)-1], b[len(b)-1])
+		l := p.F.Div(rem[len(rem)-1], b[len(b)-1])
 		pos := len(rem) - len(b)
 		r[pos] = l
-		aux := ArrayOfBigZeros(pos)
+		aux := p.zeros(pos)
 		aux1 := append(aux, l)
-		aux2 := pf.Sub(rem, pf.Mul(b, aux1))
+		aux2 := p.Sub(rem, p.Mul(b, aux1))
 		rem = aux2[:len(aux2)-1]
 	}
 	return r, rem
@@ -92,123 +113,182 @@ func max(a, b int) int {
 }
 
 // Add adds two polinomials over the Finite Field
-func (pf PolynomialField) Add(a, b []*big.Int) []*big.Int {
-	r := ArrayOfBigZeros(max(len(a), len(b)))
+func (p Polynomials[T]) Add(a, b []T) []T {
+	r := p.zeros(max(len(a), len(b)))
 	for i := 0; i < len(a); i++ {
-		r[i] = pf.F.Add(r[i], a[i])
+		r[i] = p.F.Add(r[i], a[i])
 	}
 	for i := 0; i < len(b); i++ {
-		r[i] = pf.F.Add(r[i], b[i])
+		r[i] = p.F.Add(r[i], b[i])
 	}
 	return r
 }
 
 // Sub subtracts two polinomials over the Finite Field
-func (pf PolynomialField) Sub(a, b []*big.Int) []*big.Int {
-	r := ArrayOfBigZeros(max(len(a), len(b)))
+func (p Polynomials[T]) Sub(a, b []T) []T {
+	r := p.zeros(max(len(a), len(b)))
 	for i := 0; i < len(a); i++ {
-		r[i] = pf.F.Add(r[i], a[i])
+		r[i] = p.F.Add(r[i], a[i])
 	}
 	for i := 0; i < len(b); i++ {
-		r[i] = pf.F.Sub(r[i], b[i])
+		r[i] = p.F.Sub(r[i], b[i])
 	}
 	return r
 }
 
 // Eval evaluates the polinomial over the Finite Field at the given value x
-func (pf PolynomialField) Eval(v []*big.Int, x *big.Int) *big.Int {
-	r := big.NewInt(int64(0))
+func (p Polynomials[T]) Eval(v []T, x T) T {
+	r := p.F.Zero()
 	for i := 0; i < len(v); i++ {
-		xi := pf.F.Exp(x, big.NewInt(int64(i)))
-		elem := pf.F.Mul(v[i], xi)
-		r = pf.F.Add(r, elem)
-		println(fmt.Sprintf("%v", r))
+		xi := p.F.Exp(x, big.NewInt(int64(i)))
+		elem := p.F.Mul(v[i], xi)
+		r = p.F.Add(r, elem)
 	}
 	return r
 }
 
 // NewPolZeroAt generates a new polynomial that has value zero at the given value
-func (pf PolynomialField) NewPolZeroAt(pointPos, totalPoints int, height *big.Int) []*big.Int {
-	fac := 1
+func (p Polynomials[T]) NewPolZeroAt(pointPos, totalPoints int, height T) []T {
+	fac := p.F.One()
 	for i := 1; i < totalPoints+1; i++ {
 		if i != pointPos {
-			fac = fac * (pointPos - i)
+			fac = p.F.Mul(fac, p.fromInt(pointPos-i))
 		}
 	}
-	facBig := big.NewInt(int64(fac))
-	hf := pf.F.Div(height, facBig)
-	r := []*big.Int{hf}
+	hf := p.F.Div(height, fac)
+	r := []T{hf}
 	for i := 1; i < totalPoints+1; i++ {
 		if i != pointPos {
-			ineg := big.NewInt(int64(-i))
-			b1 := big.NewInt(int64(1))
-			r = pf.Mul(r, []*big.Int{ineg, b1})
+			r = p.Mul(r, []T{p.fromInt(-i), p.F.One()})
 		}
 	}
 	return r
 }
 
 // LagrangeInterpolation performs the Lagrange Interpolation / Lagrange Polynomials operation
-func (pf PolynomialField) LagrangeInterpolation(v []*big.Int) []*big.Int {
+func (p Polynomials[T]) LagrangeInterpolation(v []T) []T {
 	// https://en.wikipedia.org/wiki/Lagrange_polynomial
-	var r []*big.Int
+	var r []T
 	for i := 0; i < len(v); i++ {
-		r = pf.Add(r, pf.NewPolZeroAt(i+1, len(v), v[i]))
+		r = p.Add(r, p.NewPolZeroAt(i+1, len(v), v[i]))
 	}
-	//
 	return r
 }
 
 // R1CSToQAP converts the R1CS values to the QAP values
-func (pf PolynomialField) R1CSToQAP(a, b, c [][]*big.Int) ([][]*big.Int, [][]*big.Int, [][]*big.Int, []*big.Int) {
-	aT := Transpose(a)
-	bT := Transpose(b)
-	cT := Transpose(c)
-	var alphas [][]*big.Int
+func (p Polynomials[T]) R1CSToQAP(a, b, c [][]T) ([][]T, [][]T, [][]T, []T) {
+	aT := transpose(a)
+	bT := transpose(b)
+	cT := transpose(c)
+	var alphas [][]T
 	for i := 0; i < len(aT); i++ {
-		alphas = append(alphas, pf.LagrangeInterpolation(aT[i]))
+		alphas = append(alphas, p.LagrangeInterpolation(aT[i]))
 	}
-	var betas [][]*big.Int
+	var betas [][]T
 	for i := 0; i < len(bT); i++ {
-		betas = append(betas, pf.LagrangeInterpolation(bT[i]))
+		betas = append(betas, p.LagrangeInterpolation(bT[i]))
 	}
-	var gammas [][]*big.Int
+	var gammas [][]T
 	for i := 0; i < len(cT); i++ {
-		gammas = append(gammas, pf.LagrangeInterpolation(cT[i]))
+		gammas = append(gammas, p.LagrangeInterpolation(cT[i]))
 	}
-	z := []*big.Int{big.NewInt(int64(1))}
+	z := []T{p.F.One()}
 	for i := 1; i < len(aT[0])+1; i++ {
-		ineg := big.NewInt(int64(-i))
-		b1 := big.NewInt(int64(1))
-		z = pf.Mul(z, []*big.Int{ineg, b1})
+		z = p.Mul(z, []T{p.fromInt(-i), p.F.One()})
 	}
 	return alphas, betas, gammas, z
 }
 
 // CombinePolynomials combine the given polynomials arrays into one, also returns the P(x)
-func (pf PolynomialField) CombinePolynomials(r []*big.Int, ap, bp, cp [][]*big.Int) ([]*big.Int, []*big.Int, []*big.Int, []*big.Int) {
-	var alpha []*big.Int
+func (p Polynomials[T]) CombinePolynomials(r []T, ap, bp, cp [][]T) ([]T, []T, []T, []T) {
+	var alpha []T
 	for i := 0; i < len(r); i++ {
-		m := pf.Mul([]*big.Int{r[i]}, ap[i])
-		alpha = pf.Add(alpha, m)
+		m := p.Mul([]T{r[i]}, ap[i])
+		alpha = p.Add(alpha, m)
 	}
-	var beta []*big.Int
+	var beta []T
 	for i := 0; i < len(r); i++ {
-		m := pf.Mul([]*big.Int{r[i]}, bp[i])
-		beta = pf.Add(beta, m)
+		m := p.Mul([]T{r[i]}, bp[i])
+		beta = p.Add(beta, m)
 	}
-	var gamma []*big.Int
+	var gamma []T
 	for i := 0; i < len(r); i++ {
-		m := pf.Mul([]*big.Int{r[i]}, cp[i])
-		gamma = pf.Add(gamma, m)
+		m := p.Mul([]T{r[i]}, cp[i])
+		gamma = p.Add(gamma, m)
 	}
 
-	px := pf.Sub(pf.Mul(alpha, beta), gamma)
+	px := p.Sub(p.Mul(alpha, beta), gamma)
 	return alpha, beta, gamma, px
 }
 
 // DivisorPolynomial returns the divisor polynomial given two polynomials
-func (pf PolynomialField) DivisorPolynomial(px, z []*big.Int) []*big.Int {
-	quo, _ := pf.Div(px, z)
+func (p Polynomials[T]) DivisorPolynomial(px, z []T) []T {
+	quo, _ := p.Div(px, z)
 	return quo
 }
+
+// PolynomialField is the Polynomial over a Finite Field where the polynomial operations are performed
+type PolynomialField struct {
+	F fields.Fq
+}
+
+// NewPolynomialField creates a new PolynomialField with the given FiniteField
+func NewPolynomialField(f fields.Fq) PolynomialField {
+	return PolynomialField{
+		f,
+	}
+}
+
+func (pf PolynomialField) polynomials() Polynomials[*big.Int] {
+	return NewPolynomials[*big.Int](pf.F)
+}
+
+// Mul multiplies two polinomials over the Finite Field
+func (pf PolynomialField) Mul(a, b []*big.Int) []*big.Int {
+	return pf.polynomials().Mul(a, b)
+}
+
+// Div divides two polinomials over the Finite Field, returning the result and the remainder
+func (pf PolynomialField) Div(a, b []*big.Int) ([]*big.Int, []*big.Int) {
+	return pf.polynomials().Div(a, b)
+}
+
+// Add adds two polinomials over the Finite Field
+func (pf PolynomialField) Add(a, b []*big.Int) []*big.Int {
+	return pf.polynomials().Add(a, b)
+}
+
+// Sub subtracts two polinomials over the Finite Field
+func (pf PolynomialField) Sub(a, b []*big.Int) []*big.Int {
+	return pf.polynomials().Sub(a, b)
+}
+
+// Eval evaluates the polinomial over the Finite Field at the given value x
+func (pf PolynomialField) Eval(v []*big.Int, x *big.Int) *big.Int {
+	return pf.polynomials().Eval(v, x)
+}
+
+// NewPolZeroAt generates a new polynomial that has value zero at the given value
+func (pf PolynomialField) NewPolZeroAt(pointPos, totalPoints int, height *big.Int) []*big.Int {
+	return pf.polynomials().NewPolZeroAt(pointPos, totalPoints, height)
+}
+
+// LagrangeInterpolation performs the Lagrange Interpolation / Lagrange Polynomials operation
+func (pf PolynomialField) LagrangeInterpolation(v []*big.Int) []*big.Int {
+	return pf.polynomials().LagrangeInterpolation(v)
+}
+
+// R1CSToQAP converts the R1CS values to the QAP values
+func (pf PolynomialField) R1CSToQAP(a, b, c [][]*big.Int) ([][]*big.Int, [][]*big.Int, [][]*big.Int, []*big.Int) {
+	return pf.polynomials().R1CSToQAP(a, b, c)
+}
+
+// CombinePolynomials combine the given polynomials arrays into one, also returns the P(x)
+func (pf PolynomialField) CombinePolynomials(r []*big.Int, ap, bp, cp [][]*big.Int) ([]*big.Int, []*big.Int, []*big.Int, []*big.Int) {
+	return pf.polynomials().CombinePolynomials(r, ap, bp, cp)
+}
+
+// DivisorPolynomial returns the divisor polynomial given two polynomials
+func (pf PolynomialField) DivisorPolynomial(px, z []*big.Int) []*big.Int {
+	return pf.polynomials().DivisorPolynomial(px, z)
+}
@@ -159,3 +159,78 @@ func TestR1CSToQAP(t *testing.T) {
 	assert.Equal(t, abc, hz)
 
 }
+
+func TestR1CSToQAPMont(t *testing.T) {
+	// the same QAP over the Montgomery form Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	pf := NewPolynomialField(f)
+	fm, err := fields.NewFqMont(r)
+	assert.Nil(t, err)
+	pm := NewPolynomials[fields.Element](fm)
+
+	toMont := func(m [][]*big.Int) [][]fields.Element {
+		var res [][]fields.Element
+		for _, row := range m {
+			var mrow []fields.Element
+			for _, v := range row {
+				mrow = append(mrow, fm.SetBig(v))
+			}
+			res = append(res, mrow)
+		}
+		return res
+	}
+	fromMont := func(p []fields.Element) []string {
+		var res []string
+		for _, v := range p {
+			res = append(res, fm.Big(v).String())
+		}
+		return res
+	}
+	strs := func(p []*big.Int) []string {
+		var res []string
+		for _, v := range p {
+			res = append(res, f.Affine(v).String())
+		}
+		return res
+	}
+
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	b5 := big.NewInt(int64(5))
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b1, b0},
+		[]*big.Int{b5, b0, b0, b0, b0, b1},
+	}
+	b := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+	}
+	c := [][]*big.Int{
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b0, b0, b0, b1, b0},
+		[]*big.Int{b0, b0, b0, b0, b0, b1},
+		[]*big.Int{b0, b0, b1, b0, b0, b0},
+	}
+	alphas, betas, gammas, zx := pf.R1CSToQAP(a, b, c)
+	malphas, mbetas, mgammas, mzx := pm.R1CSToQAP(toMont(a), toMont(b), toMont(c))
+	assert.Equal(t, strs(zx), fromMont(mzx))
+	for i := range alphas {
+		assert.Equal(t, strs(alphas[i]), fromMont(malphas[i]))
+		assert.Equal(t, strs(betas[i]), fromMont(mbetas[i]))
+		assert.Equal(t, strs(gammas[i]), fromMont(mgammas[i]))
+	}
+
+	w := toMont([][]*big.Int{{b1, big.NewInt(int64(3)), big.NewInt(int64(35)), big.NewInt(int64(9)), big.NewInt(int64(27)), big.NewInt(int64(30))}})[0]
+	_, _, _, px := pm.CombinePolynomials(w, malphas, mbetas, mgammas)
+	hx, rem := pm.Div(px, mzx)
+	for _, v := range rem {
+		assert.True(t, fm.IsZero(v))
+	}
+	assert.Equal(t, fromMont(px), fromMont(pm.Mul(hx, mzx)))
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/arnaucube/go-snark/r1csqap"
 )
 
-// Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed
+// Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed.
+// Its points are stored in JSON as the hex strings of their compressed
+// encoding (Bn128.G1ToCompressed and Bn128.G2ToCompressed)
 type Setup struct {
 	Toxic struct {
 		T      *big.Int // trusted setup secret
@@ -25,40 +27,41 @@ type Setup struct {
 		RhoC   *big.Int
 	}
 
-	// public
-	G1T [][3]*big.Int    // t encrypted in G1 curve
-	G2T [][3][2]*big.Int // t encrypted in G2 curve
+	// public, in affine coordinates
+	G1T []bn128.G1Affine // t encrypted in G1 curve
+	G2T []bn128.G2Affine // t encrypted in G2 curve
 	Pk  struct {         // Proving Key pk:=(pkA, pkB, pkC, pkH)
-		A  [][3]*big.Int
-		B  [][3][2]*big.Int
-		C  [][3]*big.Int
-		Kp [][3]*big.Int
-		Ap [][3]*big.Int
-		Bp [][3]*big.Int
-		Cp [][3]*big.Int
+		A  []bn128.G1Affine
+		B  []bn128.G2Affine
+		C  []bn128.G1Affine
+		Kp []bn128.G1Affine
+		Ap []bn128.G1Affine
+		Bp []bn128.G1Affine
+		Cp []bn128.G1Affine
 	}
 	Vk struct {
-		Vka   [3][2]*big.Int
-		Vkb   [3]*big.Int
-		Vkc   [3][2]*big.Int
-		A     [][3]*big.Int
-		G1Kbg [3]*big.Int    // g1 * Kbeta * Kgamma
-		G2Kbg [3][2]*big.Int // g2 * Kbeta * Kgamma
-		G2Kg  [3][2]*big.Int // g2 * Kgamma
-		Vkz   [3][2]*big.Int
+		Vka   bn128.G2Affine
+		Vkb   bn128.G1Affine
+		Vkc   bn128.G2Affine
+		A     []bn128.G1Affine
+		G1Kbg bn128.G1Affine // g1 * Kbeta * Kgamma
+		G2Kbg bn128.G2Affine // g2 * Kbeta * Kgamma
+		G2Kg  bn128.G2Affine // g2 * Kgamma
+		Vkz   bn128.G2Affine
 	}
 }
 
-// Proof contains the parameters to proof the zkSNARK
+// Proof contains the parameters to proof the zkSNARK, with the points in
+// affine coordinates, stored in JSON like the points of the Setup
 type Proof struct {
-	PiA           [3]*big.Int
-	PiAp          [3]*big.Int
-	PiB           [3][2]*big.Int
-	PiBp          [3]*big.Int
-	PiC           [3]*big.Int
-	PiCp          [3]*big.Int
-	PiH           [3]*big.Int
-	PiKp          [3]*big.Int
+	PiA           bn128.G1Affine
+	PiAp          bn128.G1Affine
+	PiB           bn128.G2Affine
+	PiBp          bn128.G1Affine
+	PiC           bn128.G1Affine
+	PiCp          bn128.G1Affine
+	PiH           bn128.G1Affine
+	PiKp          bn128.G1Affine
 	PublicSignals []*big.Int
 }
 
@@ -88,7 +91,8 @@ func prepareUtils() utils {
 	}
 }
 
-// GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit. The Setup.Toxic sub data structure must be destroyed
+// GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit. The Setup.Toxic sub data structure must be destroyed.
+// The points are computed with fixed-base tables of the generators, which do the same operations for any scalar (all of them are derived from the Setup.Toxic values)
 func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, alphas, betas, gammas [][]*big.Int, zx []*big.Int) (Setup, error) {
 	var setup Setup
 	var err error
@@ -133,24 +137,26 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 	}
 	setup.Toxic.RhoC = Utils.FqR.Mul(setup.Toxic.RhoA, setup.Toxic.RhoB)
 
+	// tables of the generators, for all the multiplications
+	g1Table := Utils.Bn.G1.NewTable(Utils.Bn.G1.G)
+	g2Table := Utils.Bn.G2.NewTable(Utils.Bn.G2.G)
+
 	// encrypt t values with curve generators
 	var gt1 [][3]*big.Int
 	var gt2 [][3][2]*big.Int
 	for i := 0; i < witnessLength; i++ {
 		tPow := Utils.FqR.Exp(setup.Toxic.T, big.NewInt(int64(i)))
-		tEncr1 := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, tPow)
+		tEncr1 := g1Table.MulScalar(tPow)
 		gt1 = append(gt1, tEncr1)
-		tEncr2 := Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, tPow)
+		tEncr2 := g2Table.MulScalar(tPow)
 		gt2 = append(gt2, tEncr2)
 	}
 	// gt1: g1, g1*t, g1*t^2, g1*t^3, ...
 	// gt2: g2, g2*t, g2*t^2, ...
-	setup.G1T = gt1
-	setup.G2T = gt2
 
-	setup.Vk.Vka = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Ka)
-	setup.Vk.Vkb = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kb)
-	setup.Vk.Vkc = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kc)
+	setup.Vk.Vka = bn128.G2AffineFromArray(g2Table.MulScalar(setup.Toxic.Ka))
+	setup.Vk.Vkb = bn128.G1AffineFromArray(g1Table.MulScalar(setup.Toxic.Kb))
+	setup.Vk.Vkc = bn128.G2AffineFromArray(g2Table.MulScalar(setup.Toxic.Kc))
 
 	/*
 		Verification keys:
@@ -159,30 +165,32 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 		- Vk_gamma: setup.G2Kg = g2 * Kgamma
 	*/
 	kbg := Utils.FqR.Mul(setup.Toxic.Kbeta, setup.Toxic.Kgamma)
-	setup.Vk.G1Kbg = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kbg)
-	setup.Vk.G2Kbg = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, kbg)
-	setup.Vk.G2Kg = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kgamma)
+	setup.Vk.G1Kbg = bn128.G1AffineFromArray(g1Table.MulScalar(kbg))
+	setup.Vk.G2Kbg = bn128.G2AffineFromArray(g2Table.MulScalar(kbg))
+	setup.Vk.G2Kg = bn128.G2AffineFromArray(g2Table.MulScalar(setup.Toxic.Kgamma))
 
+	var pkA, pkC, pkKp, pkAp, pkBp, pkCp, vkA [][3]*big.Int
+	var pkB [][3][2]*big.Int
 	// for i := 0; i < circuit.NSignals; i++ {
 	for i := 0; i < circuit.NVars; i++ {
 		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
-		a := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, at)
-		setup.Pk.A = append(setup.Pk.A, a)
+		a := g1Table.MulScalar(at)
+		pkA = append(pkA, a)
 		if i <= circuit.NPublic {
-			setup.Vk.A = append(setup.Vk.A, a)
+			vkA = append(vkA, a)
 		}
 
 		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
-		bg1 := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bt)
-		bg2 := Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, bt)
-		setup.Pk.B = append(setup.Pk.B, bg2)
+		bg1 := g1Table.MulScalar(bt)
+		bg2 := g2Table.MulScalar(bt)
+		pkB = append(pkB, bg2)
 
 		ct := Utils.PF.Eval(gammas[i], setup.Toxic.T)
-		c := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, ct)
-		setup.Pk.C = append(setup.Pk.C, c)
+		c := g1Table.MulScalar(ct)
+		pkC = append(pkC, c)
 
 		kt := Utils.FqR.Add(Utils.FqR.Add(at, bt), ct)
-		k := Utils.Bn.G1.Affine(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kt))
+		k := Utils.Bn.G1.Affine(g1Table.MulScalar(kt))
 
 		ktest := Utils.Bn.G1.Affine(Utils.Bn.G1.Add(Utils.Bn.G1.Add(a, bg1), c))
 		if !Utils.Bn.Fq2.Equal(k, ktest) {
@@ -190,58 +198,143 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 			return setup, err
 		}
 
-		setup.Pk.Ap = append(setup.Pk.Ap, Utils.Bn.G1.MulScalar(a, setup.Toxic.Ka))
-		setup.Pk.Bp = append(setup.Pk.Bp, Utils.Bn.G1.MulScalar(bg1, setup.Toxic.Kb))
-		setup.Pk.Cp = append(setup.Pk.Cp, Utils.Bn.G1.MulScalar(c, setup.Toxic.Kc))
-		k_ := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kt)
-		setup.Pk.Kp = append(setup.Pk.Kp, Utils.Bn.G1.MulScalar(k_, setup.Toxic.Kbeta))
+		// a*Ka = g1*(at*Ka), and the same for the others
+		pkAp = append(pkAp, g1Table.MulScalar(Utils.FqR.Mul(at, setup.Toxic.Ka)))
+		pkBp = append(pkBp, g1Table.MulScalar(Utils.FqR.Mul(bt, setup.Toxic.Kb)))
+		pkCp = append(pkCp, g1Table.MulScalar(Utils.FqR.Mul(ct, setup.Toxic.Kc)))
+		pkKp = append(pkKp, g1Table.MulScalar(Utils.FqR.Mul(kt, setup.Toxic.Kbeta)))
 	}
-	setup.Vk.Vkz = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, Utils.PF.Eval(zx, setup.Toxic.T))
+	setup.Vk.Vkz = bn128.G2AffineFromArray(g2Table.MulScalar(Utils.PF.Eval(zx, setup.Toxic.T)))
+
+	// convert the points to affine, with one inversion for each array
+	setup.G1T = bn128.G1AffinesFromArrays(gt1)
+	setup.G2T = bn128.G2AffinesFromArrays(gt2)
+	setup.Pk.A = bn128.G1AffinesFromArrays(pkA)
+	setup.Pk.B = bn128.G2AffinesFromArrays(pkB)
+	setup.Pk.C = bn128.G1AffinesFromArrays(pkC)
+	setup.Pk.Kp = bn128.G1AffinesFromArrays(pkKp)
+	setup.Pk.Ap = bn128.G1AffinesFromArrays(pkAp)
+	setup.Pk.Bp = bn128.G1AffinesFromArrays(pkBp)
+	setup.Pk.Cp = bn128.G1AffinesFromArrays(pkCp)
+	setup.Vk.A = bn128.G1AffinesFromArrays(vkA)
 
 	return setup, nil
 }
 
-// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness
+// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness.
+// The points are multiplied with the GLV (G1) and GLS (G2) endomorphisms,
+// which are not constant time: the time of the proof depends on the secret
+// witness. GenerateProofsConstantTime doesn't leak it
 func GenerateProofs(circuit circuitcompiler.Circuit, setup Setup, hx []*big.Int, w []*big.Int) (Proof, error) {
+	return generateProofs(circuit, setup, hx, w, Utils.Bn.G1.MulScalarGLV, Utils.Bn.G2.MulScalarGLS)
+}
+
+// GenerateProofsConstantTime generates the same proof as GenerateProofs,
+// multiplying the points by the witness with MulScalarConstantTime, so the
+// time of the multiplications doesn't depend on the witness. It is slower
+func GenerateProofsConstantTime(circuit circuitcompiler.Circuit, setup Setup, hx []*big.Int, w []*big.Int) (Proof, error) {
+	return generateProofs(circuit, setup, hx, w, Utils.Bn.G1.MulScalarConstantTime, Utils.Bn.G2.MulScalarConstantTime)
+}
+
+// generateProofs generates the proof with the given scalar multiplications
+func generateProofs(circuit circuitcompiler.Circuit, setup Setup, hx []*big.Int, w []*big.Int,
+	g1Mul func([3]*big.Int, *big.Int) [3]*big.Int, g2Mul func([3][2]*big.Int, *big.Int) [3][2]*big.Int) (Proof, error) {
 	var proof Proof
-	proof.PiA = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiAp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiB = Utils.Bn.Fq6.Zero()
-	proof.PiBp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiC = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiCp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiH = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiKp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piA := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piAp := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piB := Utils.Bn.Fq6.Zero()
+	piBp := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piC := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piCp := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piH := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	piKp := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
 
 	for i := circuit.NPublic + 1; i < circuit.NVars; i++ {
-		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalar(setup.Pk.A[i], w[i]))
-		proof.PiAp = Utils.Bn.G1.Add(proof.PiAp, Utils.Bn.G1.MulScalar(setup.Pk.Ap[i], w[i]))
+		piA = Utils.Bn.G1.Add(piA, g1Mul(setup.Pk.A[i].Array(), w[i]))
+		piAp = Utils.Bn.G1.Add(piAp, g1Mul(setup.Pk.Ap[i].Array(), w[i]))
 	}
 
 	for i := 0; i < circuit.NVars; i++ {
-		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalar(setup.Pk.B[i], w[i]))
-		proof.PiBp = Utils.Bn.G1.Add(proof.PiBp, Utils.Bn.G1.MulScalar(setup.Pk.Bp[i], w[i]))
+		piB = Utils.Bn.G2.Add(piB, g2Mul(setup.Pk.B[i].Array(), w[i]))
+		piBp = Utils.Bn.G1.Add(piBp, g1Mul(setup.Pk.Bp[i].Array(), w[i]))
 
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(setup.Pk.C[i], w[i]))
-		proof.PiCp = Utils.Bn.G1.Add(proof.PiCp, Utils.Bn.G1.MulScalar(setup.Pk.Cp[i], w[i]))
+		piC = Utils.Bn.G1.Add(piC, g1Mul(setup.Pk.C[i].Array(), w[i]))
+		piCp = Utils.Bn.G1.Add(piCp, g1Mul(setup.Pk.Cp[i].Array(), w[i]))
 
-		proof.PiKp = Utils.Bn.G1.Add(proof.PiKp, Utils.Bn.G1.MulScalar(setup.Pk.Kp[i], w[i]))
+		piKp = Utils.Bn.G1.Add(piKp, g1Mul(setup.Pk.Kp[i].Array(), w[i]))
 	}
 
 	for i := 0; i < len(hx); i++ {
-		proof.PiH = Utils.Bn.G1.Add(proof.PiH, Utils.Bn.G1.MulScalar(setup.G1T[i], hx[i]))
+		piH = Utils.Bn.G1.Add(piH, g1Mul(setup.G1T[i].Array(), hx[i]))
 	}
-	proof.PublicSignals = w[1:2] // out signal
+	proof.PublicSignals = w[1 : circuit.NPublic+1] // out signal, and the public inputs of the imported circuits
+
+	// convert the points to affine
+	g1s := bn128.G1AffinesFromArrays([][3]*big.Int{piA, piAp, piBp, piC, piCp, piH, piKp})
+	proof.PiA, proof.PiAp, proof.PiBp, proof.PiC, proof.PiCp, proof.PiH, proof.PiKp = g1s[0], g1s[1], g1s[2], g1s[3], g1s[4], g1s[5], g1s[6]
+	proof.PiB = bn128.G2AffineFromArray(piB)
 
 	return proof, nil
 }
 
+// PreparedVk is the verifying key of a Setup with the line coefficients of its
+// G2 points precomputed, to verify many proofs without computing them again
+type PreparedVk struct {
+	vkb   [3]*big.Int
+	g1Kbg [3]*big.Int
+	a     [][3]*big.Int
+
+	g2    bn128.AteG2Precomp
+	vka   bn128.AteG2Precomp
+	vkc   bn128.AteG2Precomp
+	g2Kbg bn128.AteG2Precomp
+	g2Kg  bn128.AteG2Precomp
+	vkz   bn128.AteG2Precomp
+}
+
+// PrepareVk returns the PreparedVk of the verifying key of the Setup
+func PrepareVk(setup Setup) PreparedVk {
+	return PreparedVk{
+		vkb:   setup.Vk.Vkb.Array(),
+		g1Kbg: setup.Vk.G1Kbg.Array(),
+		a:     bn128.G1AffinesToArrays(setup.Vk.A),
+		g2:    Utils.Bn.PrecomputeG2(Utils.Bn.G2.G),
+		vka:   Utils.Bn.PrecomputeG2(setup.Vk.Vka.Array()),
+		vkc:   Utils.Bn.PrecomputeG2(setup.Vk.Vkc.Array()),
+		g2Kbg: Utils.Bn.PrecomputeG2(setup.Vk.G2Kbg.Array()),
+		g2Kg:  Utils.Bn.PrecomputeG2(setup.Vk.G2Kg.Array()),
+		vkz:   Utils.Bn.PrecomputeG2(setup.Vk.Vkz.Array()),
+	}
+}
+
 // VerifyProof verifies over the BN128 the Pairings of the Proof
 func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, printVer bool) bool {
+	return VerifyProofPrepared(circuit, PrepareVk(setup), proof, printVer)
+}
+
+// pairingCheck returns if the product of the pairings is one
+func pairingCheck(ps1 [][3]*big.Int, pres2 []*bn128.AteG2Precomp) bool {
+	ok, err := Utils.Bn.PairingCheckPrecomputed(ps1, pres2)
+	return err == nil && ok
+}
+
+// VerifyProofPrepared verifies the Proof with the PreparedVk. Each check
+// e(a, b) == e(c, d) is done as e(a, b) * e(-c, d) == 1, with one Miller loop
+// for all the pairs and one final exponentiation. A proof with a number of
+// public signals different from the verifying key is rejected
+func VerifyProofPrepared(circuit circuitcompiler.Circuit, pvk PreparedVk, proof Proof, printVer bool) bool {
+	if len(proof.PublicSignals)+1 != len(pvk.a) {
+		return false
+	}
+	g1 := Utils.Bn.G1
+	piA, piAp, piBp := proof.PiA.Array(), proof.PiAp.Array(), proof.PiBp.Array()
+	piC, piCp, piH, piKp := proof.PiC.Array(), proof.PiCp.Array(), proof.PiH.Array(), proof.PiKp.Array()
+	piB := Utils.Bn.PrecomputeG2(proof.PiB.Array())
+
 	// e(piA, Va) == e(piA', g2)
-	pairingPiaVa := Utils.Bn.Pairing(proof.PiA, setup.Vk.Vka)
-	pairingPiapG2 := Utils.Bn.Pairing(proof.PiAp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingPiaVa, pairingPiapG2) {
+	if !pairingCheck(
+		[][3]*big.Int{piA, g1.Neg(piAp)},
+		[]*bn128.AteG2Precomp{&pvk.vka, &pvk.g2}) {
 		return false
 	}
 	if printVer {
@@ -249,9 +342,9 @@ func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, prin
 	}
 
 	// e(Vb, piB) == e(piB', g2)
-	pairingVbPib := Utils.Bn.Pairing(setup.Vk.Vkb, proof.PiB)
-	pairingPibpG2 := Utils.Bn.Pairing(proof.PiBp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingVbPib, pairingPibpG2) {
+	if !pairingCheck(
+		[][3]*big.Int{pvk.vkb, g1.Neg(piBp)},
+		[]*bn128.AteG2Precomp{&piB, &pvk.g2}) {
 		return false
 	}
 	if printVer {
@@ -259,9 +352,9 @@ func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, prin
 	}
 
 	// e(piC, Vc) == e(piC', g2)
-	pairingPicVc := Utils.Bn.Pairing(proof.PiC, setup.Vk.Vkc)
-	pairingPicpG2 := Utils.Bn.Pairing(proof.PiCp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingPicVc, pairingPicpG2) {
+	if !pairingCheck(
+		[][3]*big.Int{piC, g1.Neg(piCp)},
+		[]*bn128.AteG2Precomp{&pvk.vkc, &pvk.g2}) {
 		return false
 	}
 	if printVer {
@@ -269,17 +362,15 @@ func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, prin
 	}
 
 	// Vkx, to then calculate Vkx+piA
-	vkxpia := setup.Vk.A[0]
+	vkxpia := pvk.a[0]
 	for i := 0; i < len(proof.PublicSignals); i++ {
-		vkxpia = Utils.Bn.G1.Add(vkxpia, Utils.Bn.G1.MulScalar(setup.Vk.A[i+1], proof.PublicSignals[i]))
+		vkxpia = g1.Add(vkxpia, g1.MulScalarGLV(pvk.a[i+1], proof.PublicSignals[i]))
 	}
 
 	// e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2)
-	if !Utils.Bn.Fq12.Equal(
-		Utils.Bn.Pairing(Utils.Bn.G1.Add(vkxpia, proof.PiA), proof.PiB),
-		Utils.Bn.Fq12.Mul(
-			Utils.Bn.Pairing(proof.PiH, setup.Vk.Vkz),
-			Utils.Bn.Pairing(proof.PiC, Utils.Bn.G2.G))) {
+	if !pairingCheck(
+		[][3]*big.Int{g1.Add(vkxpia, piA), g1.Neg(piH), g1.Neg(piC)},
+		[]*bn128.AteG2Precomp{&piB, &pvk.vkz, &pvk.g2}) {
 		return false
 	}
 	if printVer {
@@ -288,12 +379,10 @@ func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, prin
 
 	// e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB)
 	// == e(piK, g2Kgamma)
-	piApiC := Utils.Bn.G1.Add(Utils.Bn.G1.Add(vkxpia, proof.PiA), proof.PiC)
-	pairingPiACG2Kbg := Utils.Bn.Pairing(piApiC, setup.Vk.G2Kbg)
-	pairingG1KbgPiB := Utils.Bn.Pairing(setup.Vk.G1Kbg, proof.PiB)
-	pairingL := Utils.Bn.Fq12.Mul(pairingPiACG2Kbg, pairingG1KbgPiB)
-	pairingR := Utils.Bn.Pairing(proof.PiKp, setup.Vk.G2Kg)
-	if !Utils.Bn.Fq12.Equal(pairingL, pairingR) {
+	piApiC := g1.Add(g1.Add(vkxpia, piA), piC)
+	if !pairingCheck(
+		[][3]*big.Int{piApiC, pvk.g1Kbg, g1.Neg(piKp)},
+		[]*bn128.AteG2Precomp{&pvk.g2Kbg, &piB, &pvk.g2Kg}) {
 		return false
 	}
 	if printVer {
@@ -1,12 +1,19 @@
 package snark
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"math/big"
+	"os"
+	"os/exec"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/arnaucube/go-snark/bn128"
 	"github.com/arnaucube/go-snark/circuitcompiler"
 	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/stretchr/testify/assert"
@@ -95,7 +102,8 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 
 	// flat code to R1CS
 	fmt.Println("\ngenerating R1CS from flat code")
-	a, b, c := circuit.GenerateR1CS()
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
 	fmt.Println("\nR1CS:")
 	fmt.Println("a:", a)
 	fmt.Println("b:", b)
@@ -234,7 +242,8 @@ func TestZkMultiplication(t *testing.T) {
 	assert.Nil(t, err)
 
 	// flat code to R1CS
-	a, b, c := circuit.GenerateR1CS()
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
 
 	// R1CS to QAP
 	alphas, betas, gammas, zx := Utils.PF.R1CSToQAP(a, b, c)
@@ -266,3 +275,471 @@ func TestZkMultiplication(t *testing.T) {
 
 	assert.True(t, VerifyProof(*circuit, setup, proof, false))
 }
+
+// testSetup generates the trusted setup of the circuit
+func testSetup(t *testing.T, circuit *circuitcompiler.Circuit) Setup {
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	alphas, betas, gammas, zx := Utils.PF.R1CSToQAP(a, b, c)
+	setup, err := GenerateTrustedSetup(len(circuit.Signals), *circuit, alphas, betas, gammas, zx)
+	assert.Nil(t, err)
+	return setup
+}
+
+// testProof generates the proof of the witness of the circuit
+func testProof(t *testing.T, circuit *circuitcompiler.Circuit, setup Setup, w []*big.Int) Proof {
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	alphas, betas, gammas, zx := Utils.PF.R1CSToQAP(a, b, c)
+	_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+	hx := Utils.PF.DivisorPolynomial(px, zx)
+	proof, err := GenerateProofs(*circuit, setup, hx, w)
+	assert.Nil(t, err)
+	return proof
+}
+
+// testSetupProof compiles the flat code, and returns the circuit with its
+// trusted setup and the proof of the given inputs
+func testSetupProof(t *testing.T, flatCode string, inputs []*big.Int) (*circuitcompiler.Circuit, Setup, Proof) {
+	parser := circuitcompiler.NewParser(strings.NewReader(flatCode))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness(inputs)
+	assert.Nil(t, err)
+	setup := testSetup(t, circuit)
+	return circuit, setup, testProof(t, circuit, setup, w)
+}
+
+func TestZkFromCircomFiles(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		ab = a * b
+		out = ab * b
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(flatCode))
+	compiled, err := parser.Parse()
+	assert.Nil(t, err)
+	w, err := compiled.CalculateWitness([]*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+	assert.Nil(t, err)
+
+	// export and import the circuit and witness as circom files
+	var r1csFile, wtnsFile bytes.Buffer
+	assert.Nil(t, compiled.WriteR1CS(&r1csFile))
+	assert.Nil(t, compiled.WriteWtns(&wtnsFile, w))
+	circuit, _, err := circuitcompiler.ReadR1CS(&r1csFile)
+	assert.Nil(t, err)
+	// the witness is the values of all the wires
+	w, err = circuitcompiler.ReadWtns(&wtnsFile)
+	assert.Nil(t, err)
+
+	setup := testSetup(t, circuit)
+	proof := testProof(t, circuit, setup, w)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(48))}, proof.PublicSignals)
+	assert.True(t, VerifyProof(*circuit, setup, proof, false))
+}
+
+func TestSnarkjsJSON(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		out = a * b
+	`
+	circuit, setup, proof := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+
+	vkJSON, err := json.Marshal(NewSnarkjsVk(*circuit, setup))
+	assert.Nil(t, err)
+	proofJSON, err := json.Marshal(NewSnarkjsProof(proof))
+	assert.Nil(t, err)
+	publicJSON, err := json.Marshal(SnarkjsPublic(proof.PublicSignals))
+	assert.Nil(t, err)
+	fmt.Println(string(vkJSON))
+	fmt.Println(string(proofJSON))
+	assert.Equal(t, `["12"]`, string(publicJSON))
+
+	var vk SnarkjsVk
+	assert.Nil(t, json.Unmarshal(vkJSON, &vk))
+	assert.Equal(t, "original", vk.Protocol)
+	assert.Equal(t, 1, vk.NPublic)
+	assert.Equal(t, "1", vk.VkB[2])
+	assert.Equal(t, [2]string{"1", "0"}, vk.VkA[2])
+	vkSetup, err := vk.Setup()
+	assert.Nil(t, err)
+
+	var snarkjsProof SnarkjsProof
+	assert.Nil(t, json.Unmarshal(proofJSON, &snarkjsProof))
+	var public []string
+	assert.Nil(t, json.Unmarshal(publicJSON, &public))
+	publicSignals, err := ParseSnarkjsPublic(public)
+	assert.Nil(t, err)
+	decodedProof, err := snarkjsProof.Proof(publicSignals)
+	assert.Nil(t, err)
+	assert.True(t, VerifyProof(*circuit, vkSetup, decodedProof, false))
+
+	// a modified proof is rejected
+	decodedProof.PiC = bn128.G1AffineFromArray(Utils.Bn.G1.Double(decodedProof.PiC.Array()))
+	assert.False(t, VerifyProof(*circuit, vkSetup, decodedProof, false))
+
+	snarkjsProof.PiA[0] = "0x12"
+	_, err = snarkjsProof.Proof(publicSignals)
+	assert.Equal(t, "pi_a: invalid number: 0x12", err.Error())
+
+	// the points are checked to be on the curve, and in the subgroup for G2
+	assert.Nil(t, json.Unmarshal(proofJSON, &snarkjsProof))
+	snarkjsProof.PiC[1] = "5"
+	_, err = snarkjsProof.Proof(publicSignals)
+	assert.Equal(t, "pi_c: G1 point not on the curve", err.Error())
+
+	assert.Nil(t, json.Unmarshal(proofJSON, &snarkjsProof))
+	snarkjsProof.PiB[0][0] = Utils.Bn.Q.String()
+	_, err = snarkjsProof.Proof(publicSignals)
+	assert.Equal(t, "pi_b: coordinate out of range: "+Utils.Bn.Q.String(), err.Error())
+
+	vk.VkZ = g2ToSnarkjs(g2NotInSubgroup(t))
+	_, err = vk.Setup()
+	assert.Equal(t, "vk_z: G2 point not in the subgroup", err.Error())
+}
+
+// g2NotInSubgroup returns a point of the twist curve which is not in the
+// subgroup of order r
+func g2NotInSubgroup(t *testing.T) [3][2]*big.Int {
+	bn := Utils.Bn
+	for i := int64(1); i < 20; i++ {
+		x := [2]*big.Int{big.NewInt(i), big.NewInt(int64(1))}
+		y, ok := bn.Fq2.Sqrt(bn.Fq2.Add(bn.Fq2.Mul(bn.Fq2.Square(x), x), bn.TwistCoefB))
+		if !ok {
+			continue
+		}
+		p := [3][2]*big.Int{x, y, bn.Fq2.One()}
+		if !bn.G2.IsZero(bn.G2.MulScalar(p, bn.R)) {
+			return p
+		}
+	}
+	t.Fatal("no point found")
+	return [3][2]*big.Int{}
+}
+
+func TestSnarkjsGroth16JSON(t *testing.T) {
+	g1 := Utils.Bn.G1.G
+	g2 := Utils.Bn.G2.G
+	g1x2 := Utils.Bn.G1.Double(g1)
+	g2x2 := Utils.Bn.G2.Double(g2)
+
+	proof := NewSnarkjsGroth16Proof(g1x2, g2x2, g1)
+	// the G2 coordinates are x0 + x1*u
+	g2json := NewSnarkjsGroth16Proof(g1, g2, g1).PiB
+	assert.Equal(t, "10857046999023057135944570762232829481370756359578518086990519993285655852781", g2json[0][0])
+	assert.Equal(t, "11559732032986387107991004021392285783925812861821192530917403151452391805634", g2json[0][1])
+
+	proofJSON, err := json.Marshal(proof)
+	assert.Nil(t, err)
+	var decoded SnarkjsGroth16Proof
+	assert.Nil(t, json.Unmarshal(proofJSON, &decoded))
+	a, b, c, err := decoded.Points()
+	assert.Nil(t, err)
+	assert.True(t, Utils.Bn.G1.Equal(g1x2, a))
+	assert.True(t, Utils.Bn.G2.Equal(g2x2, b))
+	assert.True(t, Utils.Bn.G1.Equal(g1, c))
+
+	vk := NewSnarkjsGroth16Vk(g1, g2, g2x2, g2, [][3]*big.Int{g1, g1x2})
+	assert.Equal(t, 1, vk.NPublic)
+	alpha1, beta2, _, _, ic, err := vk.Points()
+	assert.Nil(t, err)
+	assert.True(t, Utils.Bn.G1.Equal(g1, alpha1))
+	assert.True(t, Utils.Bn.G2.Equal(g2, beta2))
+	assert.Equal(t, 2, len(ic))
+
+	vk.IC[1][0] = "1"
+	_, _, _, _, _, err = vk.Points()
+	assert.Equal(t, "IC[1]: G1 point not on the curve", err.Error())
+	decoded.PiB = g2ToSnarkjs(g2NotInSubgroup(t))
+	_, _, _, err = decoded.Points()
+	assert.Equal(t, "pi_b: G2 point not in the subgroup", err.Error())
+	decoded.PiB[1][1] = "1"
+	_, _, _, err = decoded.Points()
+	assert.Equal(t, "pi_b: G2 point not on the curve", err.Error())
+}
+
+func TestSolidityVerifier(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		out = a * b
+	`
+	circuit, setup, proof := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+
+	var contract bytes.Buffer
+	assert.Nil(t, WriteSolidityVerifier(&contract, *circuit, setup))
+	vkb := setup.Vk.Vkb
+	vka := setup.Vk.Vka
+	assert.True(t, strings.Contains(contract.String(), "vk.B = Pairing.G1Point("+vkb.X.String()+", "+vkb.Y.String()+");"))
+	// the G2 coordinates are imaginary first
+	assert.True(t, strings.Contains(contract.String(), "vk.A = Pairing.G2Point(["+vka.X[1].String()+", "+vka.X[0].String()+"]"))
+	assert.True(t, strings.Contains(contract.String(), "vk.IC = new Pairing.G1Point[](2);"))
+	assert.True(t, strings.Contains(contract.String(), "uint[1] memory input"))
+
+	calldata := NewVerifierCalldata(proof)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(12))}, calldata.Input)
+	data := calldata.ABIEncode()
+	assert.Equal(t, (8*2+2+1)*32, len(data))
+	assert.Equal(t, big.NewInt(int64(12)), new(big.Int).SetBytes(data[len(data)-32:]))
+
+	// the proof rebuilt from the calldata is valid
+	g1 := func(p [2]*big.Int) bn128.G1Affine {
+		return bn128.G1Affine{X: p[0], Y: p[1]}
+	}
+	decodedProof := Proof{
+		PiA:  g1(calldata.A),
+		PiAp: g1(calldata.Ap),
+		PiB: bn128.G2Affine{
+			X: [2]*big.Int{calldata.B[0][1], calldata.B[0][0]},
+			Y: [2]*big.Int{calldata.B[1][1], calldata.B[1][0]},
+		},
+		PiBp:          g1(calldata.Bp),
+		PiC:           g1(calldata.C),
+		PiCp:          g1(calldata.Cp),
+		PiH:           g1(calldata.H),
+		PiKp:          g1(calldata.K),
+		PublicSignals: calldata.Input,
+	}
+	assert.True(t, VerifyProof(*circuit, setup, decodedProof, false))
+}
+
+// solidityVk returns the points of the verifying key of the Solidity verifier,
+// by their name in the contract (vk.A, vk.IC[0], ..., and P2)
+func solidityVk(t *testing.T, contract string) map[string][]*big.Int {
+	vk := make(map[string][]*big.Int)
+	points := regexp.MustCompile(`(?s)(vk\.\w+(?:\[\d+\])?|P2\(\) internal pure returns \(G2Point memory\) \{\s*return) (?:= )?(?:Pairing\.)?G[12]Point\(([^;]*)\);`)
+	for _, m := range points.FindAllStringSubmatch(contract, -1) {
+		name := m[1]
+		if strings.HasPrefix(name, "P2") {
+			name = "P2"
+		}
+		for _, n := range regexp.MustCompile(`\d+`).FindAllString(m[2], -1) {
+			v, ok := new(big.Int).SetString(n, 10)
+			assert.True(t, ok)
+			vk[name] = append(vk[name], v)
+		}
+	}
+	return vk
+}
+
+// solidityPairing returns the result of the EIP-197 precompile for the pairs
+// of G1 and G2 points, encoded like the pairing function of the contract: the
+// G1 coordinates, and the G2 coordinates in the order of the G2Point struct
+func solidityPairing(t *testing.T, pairs ...[]*big.Int) bool {
+	var input []byte
+	for _, pair := range pairs {
+		assert.Equal(t, 6, len(pair))
+		for _, v := range pair {
+			word := make([]byte, 32)
+			copy(word[32-len(v.Bytes()):], v.Bytes())
+			input = append(input, word...)
+		}
+	}
+	out, err := Utils.Bn.PairingCheckBytes(input)
+	return err == nil && out[31] == 1
+}
+
+// TestSolidityVerifierPairings runs the pairing checks of the verify function
+// of the contract, with its verifying key and the verifyProof calldata, using
+// the EIP-196 and EIP-197 operations of the bn128 package
+func TestSolidityVerifierPairings(t *testing.T) {
+	// out is also read, so the point of its public input is not infinity
+	flatCode := `
+	func test(a, b):
+		out = a * b
+		c = out * b
+	`
+	circuit, setup, proof := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+	var contract bytes.Buffer
+	assert.Nil(t, WriteSolidityVerifier(&contract, *circuit, setup))
+	vk := solidityVk(t, contract.String())
+	assert.Equal(t, 4, len(vk["P2"]))
+	assert.Equal(t, 2, len(vk["vk.IC[1]"]))
+
+	g1 := func(p []*big.Int) [3]*big.Int {
+		a, err := Utils.Bn.G1FromBytes(append(make([]byte, 32-len(p[0].Bytes())), append(p[0].Bytes(),
+			append(make([]byte, 32-len(p[1].Bytes())), p[1].Bytes()...)...)...))
+		assert.Nil(t, err)
+		return a
+	}
+	coords := func(p [3]*big.Int) []*big.Int {
+		b := Utils.Bn.G1ToBytes(p)
+		return []*big.Int{new(big.Int).SetBytes(b[:32]), new(big.Int).SetBytes(b[32:])}
+	}
+	add := func(p1, p2 []*big.Int) []*big.Int {
+		return coords(Utils.Bn.G1.Add(g1(p1), g1(p2)))
+	}
+	neg := func(p []*big.Int) []*big.Int {
+		return coords(Utils.Bn.G1.Neg(g1(p)))
+	}
+	pair := func(p1 []*big.Int, p2 []*big.Int) []*big.Int {
+		return append(append([]*big.Int{}, p1...), p2...)
+	}
+
+	checks := func(cd VerifierCalldata) []bool {
+		g2 := func(p [2][2]*big.Int) []*big.Int {
+			return []*big.Int{p[0][0], p[0][1], p[1][0], p[1][1]}
+		}
+		a, ap, b, bp := cd.A[:], cd.Ap[:], g2(cd.B), cd.Bp[:]
+		c, cp, h, k := cd.C[:], cd.Cp[:], cd.H[:], cd.K[:]
+		vkx := vk["vk.IC[0]"]
+		for i, in := range cd.Input {
+			ic := vk[fmt.Sprintf("vk.IC[%d]", i+1)]
+			vkx = add(vkx, coords(Utils.Bn.G1.MulScalar(g1(ic), in)))
+		}
+		return []bool{
+			solidityPairing(t, pair(a, vk["vk.A"]), pair(neg(ap), vk["P2"])),
+			solidityPairing(t, pair(vk["vk.B"], b), pair(neg(bp), vk["P2"])),
+			solidityPairing(t, pair(c, vk["vk.C"]), pair(neg(cp), vk["P2"])),
+			solidityPairing(t, pair(k, vk["vk.gamma"]), pair(neg(add(vkx, add(a, c))), vk["vk.gammaBeta2"]), pair(neg(vk["vk.gammaBeta1"]), b)),
+			solidityPairing(t, pair(add(vkx, a), b), pair(neg(h), vk["vk.Z"]), pair(neg(c), vk["P2"])),
+		}
+	}
+	calldata := NewVerifierCalldata(proof)
+	assert.Equal(t, []bool{true, true, true, true, true}, checks(calldata))
+
+	// a wrong public input fails the checks of vk_x
+	calldata.Input = []*big.Int{big.NewInt(int64(13))}
+	assert.Equal(t, []bool{true, true, true, false, false}, checks(calldata))
+
+	// piB with the G2 coordinates real first fails the checks that use it
+	calldata = NewVerifierCalldata(proof)
+	calldata.B = [2][2]*big.Int{{calldata.B[0][1], calldata.B[0][0]}, {calldata.B[1][1], calldata.B[1][0]}}
+	assert.Equal(t, []bool{true, false, true, false, false}, checks(calldata))
+}
+
+var updateGolden = flag.Bool("update", false, "update the golden files of testdata")
+
+// testVkSetup returns a Setup with a fixed verifying key for nPublic public
+// signals, to compare the generated verifier with the golden files
+func testVkSetup(nPublic int) Setup {
+	g1 := func(k int64) bn128.G1Affine {
+		return bn128.G1AffineFromArray(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, big.NewInt(k)))
+	}
+	g2 := func(k int64) bn128.G2Affine {
+		return bn128.G2AffineFromArray(Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, big.NewInt(k)))
+	}
+	var setup Setup
+	setup.Vk.Vka = g2(2)
+	setup.Vk.Vkb = g1(3)
+	setup.Vk.Vkc = g2(4)
+	setup.Vk.G1Kbg = g1(5)
+	setup.Vk.G2Kbg = g2(5)
+	setup.Vk.G2Kg = g2(6)
+	setup.Vk.Vkz = g2(7)
+	for i := 0; i <= nPublic; i++ {
+		setup.Vk.A = append(setup.Vk.A, g1(int64(8+i)))
+	}
+	return setup
+}
+
+func TestSolidityVerifierGolden(t *testing.T) {
+	for _, nPublic := range []int{0, 1} {
+		var contract bytes.Buffer
+		assert.Nil(t, WriteSolidityVerifier(&contract, circuitcompiler.Circuit{NPublic: nPublic}, testVkSetup(nPublic)))
+		file := fmt.Sprintf("testdata/verifier_%d.sol", nPublic)
+		if *updateGolden {
+			assert.Nil(t, os.WriteFile(file, contract.Bytes(), 0644))
+		}
+		golden, err := os.ReadFile(file)
+		assert.Nil(t, err)
+		assert.Equal(t, string(golden), contract.String())
+
+		// compile it when solc is available
+		if solc, err := exec.LookPath("solc"); err == nil {
+			out, err := exec.Command(solc, "--bin", file).CombinedOutput()
+			assert.Nil(t, err, string(out))
+		}
+	}
+
+	// without public signals, the calldata has no input array
+	zero := [2]*big.Int{big.NewInt(int64(0)), big.NewInt(int64(0))}
+	cd := VerifierCalldata{A: zero, Ap: zero, B: [2][2]*big.Int{zero, zero}, Bp: zero, C: zero, Cp: zero, H: zero, K: zero}
+	assert.False(t, strings.HasSuffix(cd.String(), ",[]"))
+	assert.Equal(t, 18, strings.Count(cd.String(), "\"0x"))
+	assert.Equal(t, 18*32, len(cd.ABIEncode()))
+}
+
+func TestSetupProofJSON(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		out = a * b
+	`
+	circuit, setup, proof := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+
+	setupJSON, err := json.Marshal(setup)
+	assert.Nil(t, err)
+	proofJSON, err := json.Marshal(proof)
+	assert.Nil(t, err)
+	fmt.Println(string(proofJSON))
+
+	var decodedSetup Setup
+	assert.Nil(t, json.Unmarshal(setupJSON, &decodedSetup))
+	assert.Equal(t, setup.Toxic.T, decodedSetup.Toxic.T)
+	assert.True(t, setup.Pk.B[1].Equal(decodedSetup.Pk.B[1]))
+	var decodedProof Proof
+	assert.Nil(t, json.Unmarshal(proofJSON, &decodedProof))
+	assert.Equal(t, proof.PublicSignals, decodedProof.PublicSignals)
+	assert.True(t, VerifyProof(*circuit, decodedSetup, decodedProof, false))
+
+	// a point which is not on the curve is rejected
+	var p map[string]interface{}
+	assert.Nil(t, json.Unmarshal(proofJSON, &p))
+	p["PiC"] = "0000000000000000000000000000000000000000000000000000000000000000"
+	proofJSON, err = json.Marshal(p)
+	assert.Nil(t, err)
+	assert.NotNil(t, json.Unmarshal(proofJSON, &decodedProof))
+}
+
+func TestVerifyProofPrepared(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		out = a * b
+	`
+	circuit, setup, _ := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+
+	// the same PreparedVk verifies the proofs of different witnesses
+	pvk := PrepareVk(setup)
+	for _, inputs := range [][]*big.Int{
+		{big.NewInt(int64(3)), big.NewInt(int64(4))},
+		{big.NewInt(int64(5)), big.NewInt(int64(7))},
+	} {
+		w, err := circuit.CalculateWitness(inputs)
+		assert.Nil(t, err)
+		proof := testProof(t, circuit, setup, w)
+		assert.True(t, VerifyProofPrepared(*circuit, pvk, proof, false))
+
+		// a wrong number of public signals is rejected
+		signals := proof.PublicSignals
+		proof.PublicSignals = append(append([]*big.Int{}, signals...), big.NewInt(int64(1)))
+		assert.False(t, VerifyProofPrepared(*circuit, pvk, proof, false))
+		proof.PublicSignals = nil
+		assert.False(t, VerifyProofPrepared(*circuit, pvk, proof, false))
+		proof.PublicSignals = signals
+
+		// a modified proof is rejected
+		proof.PiC = bn128.G1AffineFromArray(Utils.Bn.G1.Double(proof.PiC.Array()))
+		assert.False(t, VerifyProofPrepared(*circuit, pvk, proof, false))
+	}
+}
+
+func TestGenerateProofsConstantTime(t *testing.T) {
+	flatCode := `
+	func test(a, b):
+		out = a * b
+		c = out * b
+	`
+	circuit, setup, proof := testSetupProof(t, flatCode, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+	assert.Nil(t, err)
+	a, b, c, err := circuit.GenerateR1CS()
+	assert.Nil(t, err)
+	alphas, betas, gammas, zx := Utils.PF.R1CSToQAP(a, b, c)
+	_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+	hx := Utils.PF.DivisorPolynomial(px, zx)
+
+	// the constant time multiplications give the same proof
+	proofCT, err := GenerateProofsConstantTime(*circuit, setup, hx, w)
+	assert.Nil(t, err)
+	assert.Equal(t, proof, proofCT)
+	assert.True(t, VerifyProof(*circuit, setup, proofCT, false))
+}
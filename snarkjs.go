@@ -0,0 +1,309 @@
+package snark
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// The snarkjs JSON files store the points in affine form, as the arrays of
+// decimal strings [x, y, "1"] for G1 and [[x0, x1], [y0, y1], ["1", "0"]] for
+// G2, where (x0, x1) is x0 + x1*u, and the infinity point has z = 0
+
+// SnarkjsVk is the snarkjs verification_key.json of the original (PGHR13)
+// protocol
+type SnarkjsVk struct {
+	Protocol string       `json:"protocol"`
+	NPublic  int          `json:"nPublic"`
+	IC       [][3]string  `json:"IC"`
+	VkA      [3][2]string `json:"vk_a"`
+	VkB      [3]string    `json:"vk_b"`
+	VkC      [3][2]string `json:"vk_c"`
+	VkGb1    [3]string    `json:"vk_gb_1"`
+	VkGb2    [3][2]string `json:"vk_gb_2"`
+	VkG      [3][2]string `json:"vk_g"`
+	VkZ      [3][2]string `json:"vk_z"`
+}
+
+// SnarkjsProof is the snarkjs proof.json of the original (PGHR13) protocol
+type SnarkjsProof struct {
+	Protocol string       `json:"protocol"`
+	PiA      [3]string    `json:"pi_a"`
+	PiAp     [3]string    `json:"pi_ap"`
+	PiB      [3][2]string `json:"pi_b"`
+	PiBp     [3]string    `json:"pi_bp"`
+	PiC      [3]string    `json:"pi_c"`
+	PiCp     [3]string    `json:"pi_cp"`
+	PiH      [3]string    `json:"pi_h"`
+	PiKp     [3]string    `json:"pi_kp"`
+}
+
+// SnarkjsGroth16Vk is the snarkjs verification_key.json of the Groth16 protocol
+type SnarkjsGroth16Vk struct {
+	Protocol      string          `json:"protocol"`
+	Curve         string          `json:"curve"`
+	NPublic       int             `json:"nPublic"`
+	VkAlpha1      [3]string       `json:"vk_alpha_1"`
+	VkBeta2       [3][2]string    `json:"vk_beta_2"`
+	VkGamma2      [3][2]string    `json:"vk_gamma_2"`
+	VkDelta2      [3][2]string    `json:"vk_delta_2"`
+	VkAlphabeta12 [2][3][2]string `json:"vk_alphabeta_12"`
+	IC            [][3]string     `json:"IC"`
+}
+
+// SnarkjsGroth16Proof is the snarkjs proof.json of the Groth16 protocol
+type SnarkjsGroth16Proof struct {
+	PiA      [3]string    `json:"pi_a"`
+	PiB      [3][2]string `json:"pi_b"`
+	PiC      [3]string    `json:"pi_c"`
+	Protocol string       `json:"protocol"`
+	Curve    string       `json:"curve"`
+}
+
+func g1ToSnarkjs(p [3]*big.Int) [3]string {
+	if Utils.Bn.G1.IsZero(p) {
+		return [3]string{"0", "1", "0"}
+	}
+	a := Utils.Bn.G1.Affine(p)
+	return [3]string{a[0].String(), a[1].String(), "1"}
+}
+
+func g2ToSnarkjs(p [3][2]*big.Int) [3][2]string {
+	a := Utils.Bn.G2.Affine(p)
+	var s [3][2]string
+	for i := range a {
+		s[i] = [2]string{a[i][0].String(), a[i][1].String()}
+	}
+	return s
+}
+
+// snarkjsDecoder parses the decimal strings and the points, keeping the first
+// error. The points are checked to be on the curve, and in the subgroup of
+// order r for the G2 points, as when decoding the Setup and Proof
+type snarkjsDecoder struct {
+	err error
+}
+
+func (d *snarkjsDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+func (d *snarkjsDecoder) bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		d.fail(errors.New("invalid number: " + s))
+		return big.NewInt(int64(0))
+	}
+	return n
+}
+
+// coords parses the coordinates of the point of the field, which must be
+// lower than q, returning nil on error
+func (d *snarkjsDecoder) coords(field string, s ...string) []*big.Int {
+	if d.err != nil {
+		return nil
+	}
+	var c []*big.Int
+	for _, e := range s {
+		n, ok := new(big.Int).SetString(e, 10)
+		if !ok {
+			d.fail(errors.New(field + ": invalid number: " + e))
+			return nil
+		}
+		if n.Sign() < 0 || n.Cmp(Utils.Bn.Q) >= 0 {
+			d.fail(errors.New(field + ": coordinate out of range: " + e))
+			return nil
+		}
+		c = append(c, n)
+	}
+	return c
+}
+func (d *snarkjsDecoder) g1(field string, s [3]string) bn128.G1Affine {
+	c := d.coords(field, s[:]...)
+	if c == nil {
+		return bn128.G1Affine{}
+	}
+	p := bn128.G1AffineFromArray([3]*big.Int{c[0], c[1], c[2]})
+	if !p.IsOnCurve() {
+		d.fail(errors.New(field + ": G1 point not on the curve"))
+	}
+	return p
+}
+func (d *snarkjsDecoder) g2(field string, s [3][2]string) bn128.G2Affine {
+	c := d.coords(field, s[0][0], s[0][1], s[1][0], s[1][1], s[2][0], s[2][1])
+	if c == nil {
+		return bn128.G2Affine{}
+	}
+	p := bn128.G2AffineFromArray([3][2]*big.Int{{c[0], c[1]}, {c[2], c[3]}, {c[4], c[5]}})
+	if !p.IsOnCurve() {
+		d.fail(errors.New(field + ": G2 point not on the curve"))
+	} else if !p.IsInSubgroup() {
+		d.fail(errors.New(field + ": G2 point not in the subgroup"))
+	}
+	return p
+}
+
+// NewSnarkjsVk returns the snarkjs verification key of the Setup
+func NewSnarkjsVk(circuit circuitcompiler.Circuit, setup Setup) SnarkjsVk {
+	vk := SnarkjsVk{
+		Protocol: "original",
+		NPublic:  circuit.NPublic,
+		VkA:      g2ToSnarkjs(setup.Vk.Vka.Array()),
+		VkB:      g1ToSnarkjs(setup.Vk.Vkb.Array()),
+		VkC:      g2ToSnarkjs(setup.Vk.Vkc.Array()),
+		VkGb1:    g1ToSnarkjs(setup.Vk.G1Kbg.Array()),
+		VkGb2:    g2ToSnarkjs(setup.Vk.G2Kbg.Array()),
+		VkG:      g2ToSnarkjs(setup.Vk.G2Kg.Array()),
+		VkZ:      g2ToSnarkjs(setup.Vk.Vkz.Array()),
+	}
+	for _, a := range setup.Vk.A {
+		vk.IC = append(vk.IC, g1ToSnarkjs(a.Array()))
+	}
+	return vk
+}
+
+// Setup returns a Setup with the verification key, to be used by VerifyProof
+func (vk SnarkjsVk) Setup() (Setup, error) {
+	if vk.Protocol != "original" {
+		return Setup{}, errors.New("unsupported protocol: " + vk.Protocol)
+	}
+	var d snarkjsDecoder
+	var setup Setup
+	setup.Vk.Vka = d.g2("vk_a", vk.VkA)
+	setup.Vk.Vkb = d.g1("vk_b", vk.VkB)
+	setup.Vk.Vkc = d.g2("vk_c", vk.VkC)
+	setup.Vk.G1Kbg = d.g1("vk_gb_1", vk.VkGb1)
+	setup.Vk.G2Kbg = d.g2("vk_gb_2", vk.VkGb2)
+	setup.Vk.G2Kg = d.g2("vk_g", vk.VkG)
+	setup.Vk.Vkz = d.g2("vk_z", vk.VkZ)
+	for i, ic := range vk.IC {
+		setup.Vk.A = append(setup.Vk.A, d.g1(fmt.Sprintf("IC[%d]", i), ic))
+	}
+	return setup, d.err
+}
+
+// NewSnarkjsProof returns the snarkjs proof of the Proof
+func NewSnarkjsProof(proof Proof) SnarkjsProof {
+	return SnarkjsProof{
+		Protocol: "original",
+		PiA:      g1ToSnarkjs(proof.PiA.Array()),
+		PiAp:     g1ToSnarkjs(proof.PiAp.Array()),
+		PiB:      g2ToSnarkjs(proof.PiB.Array()),
+		PiBp:     g1ToSnarkjs(proof.PiBp.Array()),
+		PiC:      g1ToSnarkjs(proof.PiC.Array()),
+		PiCp:     g1ToSnarkjs(proof.PiCp.Array()),
+		PiH:      g1ToSnarkjs(proof.PiH.Array()),
+		PiKp:     g1ToSnarkjs(proof.PiKp.Array()),
+	}
+}
+
+// Proof returns the Proof with the given public signals (as read by
+// ParseSnarkjsPublic)
+func (p SnarkjsProof) Proof(publicSignals []*big.Int) (Proof, error) {
+	if p.Protocol != "original" {
+		return Proof{}, errors.New("unsupported protocol: " + p.Protocol)
+	}
+	var d snarkjsDecoder
+	proof := Proof{
+		PiA:           d.g1("pi_a", p.PiA),
+		PiAp:          d.g1("pi_ap", p.PiAp),
+		PiB:           d.g2("pi_b", p.PiB),
+		PiBp:          d.g1("pi_bp", p.PiBp),
+		PiC:           d.g1("pi_c", p.PiC),
+		PiCp:          d.g1("pi_cp", p.PiCp),
+		PiH:           d.g1("pi_h", p.PiH),
+		PiKp:          d.g1("pi_kp", p.PiKp),
+		PublicSignals: publicSignals,
+	}
+	return proof, d.err
+}
+
+// SnarkjsPublic returns the snarkjs public.json of the public signals
+func SnarkjsPublic(publicSignals []*big.Int) []string {
+	s := []string{}
+	for _, v := range publicSignals {
+		s = append(s, v.String())
+	}
+	return s
+}
+
+// ParseSnarkjsPublic parses the snarkjs public.json public signals
+func ParseSnarkjsPublic(public []string) ([]*big.Int, error) {
+	var d snarkjsDecoder
+	var signals []*big.Int
+	for _, s := range public {
+		signals = append(signals, d.bigInt(s))
+	}
+	return signals, d.err
+}
+
+// NewSnarkjsGroth16Vk returns the snarkjs Groth16 verification key of the
+// given points, where ic are the points of the public signals
+func NewSnarkjsGroth16Vk(alpha1 [3]*big.Int, beta2, gamma2, delta2 [3][2]*big.Int, ic [][3]*big.Int) SnarkjsGroth16Vk {
+	vk := SnarkjsGroth16Vk{
+		Protocol: "groth16",
+		Curve:    "bn128",
+		NPublic:  len(ic) - 1,
+		VkAlpha1: g1ToSnarkjs(alpha1),
+		VkBeta2:  g2ToSnarkjs(beta2),
+		VkGamma2: g2ToSnarkjs(gamma2),
+		VkDelta2: g2ToSnarkjs(delta2),
+	}
+	alphabeta := Utils.Bn.Pairing(alpha1, beta2)
+	for i := range alphabeta {
+		for j := range alphabeta[i] {
+			vk.VkAlphabeta12[i][j] = [2]string{alphabeta[i][j][0].String(), alphabeta[i][j][1].String()}
+		}
+	}
+	for _, p := range ic {
+		vk.IC = append(vk.IC, g1ToSnarkjs(p))
+	}
+	return vk
+}
+
+// Points returns the points of the Groth16 verification key
+func (vk SnarkjsGroth16Vk) Points() (alpha1 [3]*big.Int, beta2, gamma2, delta2 [3][2]*big.Int, ic [][3]*big.Int, err error) {
+	if vk.Protocol != "groth16" {
+		err = errors.New("unsupported protocol: " + vk.Protocol)
+		return
+	}
+	var d snarkjsDecoder
+	alpha1 = d.g1("vk_alpha_1", vk.VkAlpha1).Array()
+	beta2 = d.g2("vk_beta_2", vk.VkBeta2).Array()
+	gamma2 = d.g2("vk_gamma_2", vk.VkGamma2).Array()
+	delta2 = d.g2("vk_delta_2", vk.VkDelta2).Array()
+	for i, p := range vk.IC {
+		ic = append(ic, d.g1(fmt.Sprintf("IC[%d]", i), p).Array())
+	}
+	err = d.err
+	return
+}
+
+// NewSnarkjsGroth16Proof returns the snarkjs Groth16 proof of the given points
+func NewSnarkjsGroth16Proof(a [3]*big.Int, b [3][2]*big.Int, c [3]*big.Int) SnarkjsGroth16Proof {
+	return SnarkjsGroth16Proof{
+		PiA:      g1ToSnarkjs(a),
+		PiB:      g2ToSnarkjs(b),
+		PiC:      g1ToSnarkjs(c),
+		Protocol: "groth16",
+		Curve:    "bn128",
+	}
+}
+
+// Points returns the points of the Groth16 proof
+func (p SnarkjsGroth16Proof) Points() (a [3]*big.Int, b [3][2]*big.Int, c [3]*big.Int, err error) {
+	if p.Protocol != "groth16" {
+		err = errors.New("unsupported protocol: " + p.Protocol)
+		return
+	}
+	var d snarkjsDecoder
+	a = d.g1("pi_a", p.PiA).Array()
+	b = d.g2("pi_b", p.PiB).Array()
+	c = d.g1("pi_c", p.PiC).Array()
+	err = d.err
+	return
+}
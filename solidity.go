@@ -0,0 +1,374 @@
+package snark
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/template"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// The Solidity verifier uses the EIP-196 and EIP-197 precompiled contracts,
+// which take the points in affine form, with the G2 coordinates as
+// (imaginary, real), and the infinity point as (0, 0)
+
+// solidityG1 returns the coordinates of the G1 point
+func solidityG1(p bn128.G1Affine) [2]*big.Int {
+	if p.IsInfinity() {
+		return [2]*big.Int{big.NewInt(int64(0)), big.NewInt(int64(0))}
+	}
+	return [2]*big.Int{Utils.Bn.Fq1.Affine(p.X), Utils.Bn.Fq1.Affine(p.Y)}
+}
+
+// solidityG2 returns the coordinates of the G2 point, with the imaginary part
+// first
+func solidityG2(p bn128.G2Affine) [2][2]*big.Int {
+	if p.IsInfinity() {
+		zero := big.NewInt(int64(0))
+		return [2][2]*big.Int{{zero, zero}, {zero, zero}}
+	}
+	x, y := Utils.Bn.Fq2.Affine(p.X), Utils.Bn.Fq2.Affine(p.Y)
+	return [2][2]*big.Int{{x[1], x[0]}, {y[1], y[0]}}
+}
+
+// VerifierCalldata is the arguments of the verifyProof function of the
+// Solidity verifier generated by WriteSolidityVerifier
+type VerifierCalldata struct {
+	A     [2]*big.Int
+	Ap    [2]*big.Int
+	B     [2][2]*big.Int
+	Bp    [2]*big.Int
+	C     [2]*big.Int
+	Cp    [2]*big.Int
+	H     [2]*big.Int
+	K     [2]*big.Int
+	Input []*big.Int
+}
+
+// NewVerifierCalldata returns the arguments of the verifyProof function of the
+// Solidity verifier for the Proof
+func NewVerifierCalldata(proof Proof) VerifierCalldata {
+	var input []*big.Int
+	for _, v := range proof.PublicSignals {
+		input = append(input, Utils.FqR.Affine(v))
+	}
+	return VerifierCalldata{
+		A:     solidityG1(proof.PiA),
+		Ap:    solidityG1(proof.PiAp),
+		B:     solidityG2(proof.PiB),
+		Bp:    solidityG1(proof.PiBp),
+		C:     solidityG1(proof.PiC),
+		Cp:    solidityG1(proof.PiCp),
+		H:     solidityG1(proof.PiH),
+		K:     solidityG1(proof.PiKp),
+		Input: input,
+	}
+}
+
+// words returns the arguments as the list of uint256 values, in order
+func (cd VerifierCalldata) words() []*big.Int {
+	var words []*big.Int
+	words = append(words, cd.A[:]...)
+	words = append(words, cd.Ap[:]...)
+	words = append(words, cd.B[0][:]...)
+	words = append(words, cd.B[1][:]...)
+	words = append(words, cd.Bp[:]...)
+	words = append(words, cd.C[:]...)
+	words = append(words, cd.Cp[:]...)
+	words = append(words, cd.H[:]...)
+	words = append(words, cd.K[:]...)
+	words = append(words, cd.Input...)
+	return words
+}
+
+// ABIEncode returns the ABI encoding of the arguments, to be appended to the
+// selector of the verifyProof function in the transaction data
+func (cd VerifierCalldata) ABIEncode() []byte {
+	var data []byte
+	for _, w := range cd.words() {
+		word := make([]byte, 32)
+		b := w.Bytes()
+		copy(word[32-len(b):], b)
+		data = append(data, word...)
+	}
+	return data
+}
+
+func hexArray(values ...*big.Int) string {
+	var s []string
+	for _, v := range values {
+		s = append(s, fmt.Sprintf("\"0x%064x\"", v))
+	}
+	return "[" + strings.Join(s, ",") + "]"
+}
+
+// String returns the arguments as the parameters of a contract call, like
+// ["0x..","0x.."],["0x..","0x.."],[["0x..","0x.."],["0x..","0x.."]],...
+// without the input array when there are no public signals
+func (cd VerifierCalldata) String() string {
+	args := []string{
+		hexArray(cd.A[:]...),
+		hexArray(cd.Ap[:]...),
+		"[" + hexArray(cd.B[0][:]...) + "," + hexArray(cd.B[1][:]...) + "]",
+		hexArray(cd.Bp[:]...),
+		hexArray(cd.C[:]...),
+		hexArray(cd.Cp[:]...),
+		hexArray(cd.H[:]...),
+		hexArray(cd.K[:]...),
+	}
+	if len(cd.Input) > 0 {
+		args = append(args, hexArray(cd.Input...))
+	}
+	return strings.Join(args, ",")
+}
+
+// WriteSolidityVerifier writes a Solidity contract with the verifying key of
+// the Setup, which verifies the proofs with the same pairing checks of
+// VerifyProof. Its verifyProof function takes the arguments of
+// NewVerifierCalldata, without the input array when the circuit has no public
+// signals
+func WriteSolidityVerifier(w io.Writer, circuit circuitcompiler.Circuit, setup Setup) error {
+	if len(setup.Vk.A) != circuit.NPublic+1 {
+		return fmt.Errorf("the verifying key has %d points for %d public signals", len(setup.Vk.A), circuit.NPublic)
+	}
+	data := struct {
+		NPublic                   int
+		G2, Vka, Vkc, G2Kg, G2Kbg [2][2]*big.Int
+		Vkz                       [2][2]*big.Int
+		Vkb, G1Kbg                [2]*big.Int
+		IC                        [][2]*big.Int
+	}{
+		NPublic: circuit.NPublic,
+		G2:      solidityG2(bn128.G2AffineFromArray(Utils.Bn.G2.G)),
+		Vka:     solidityG2(setup.Vk.Vka),
+		Vkc:     solidityG2(setup.Vk.Vkc),
+		G2Kg:    solidityG2(setup.Vk.G2Kg),
+		G2Kbg:   solidityG2(setup.Vk.G2Kbg),
+		Vkz:     solidityG2(setup.Vk.Vkz),
+		Vkb:     solidityG1(setup.Vk.Vkb),
+		G1Kbg:   solidityG1(setup.Vk.G1Kbg),
+	}
+	for _, a := range setup.Vk.A {
+		data.IC = append(data.IC, solidityG1(a))
+	}
+	return verifierTemplate.Execute(w, data)
+}
+
+var verifierTemplate = template.Must(template.New("verifier").Parse(`// SPDX-License-Identifier: GPL-3.0
+// Verifier of the go-snark proofs, generated by go-snark
+pragma solidity >=0.6.0 <0.9.0;
+
+library Pairing {
+    struct G1Point {
+        uint X;
+        uint Y;
+    }
+    // the coordinates are X[0] * i + X[1]
+    struct G2Point {
+        uint[2] X;
+        uint[2] Y;
+    }
+
+    function P1() internal pure returns (G1Point memory) {
+        return G1Point(1, 2);
+    }
+
+    function P2() internal pure returns (G2Point memory) {
+        return G2Point(
+            [{{index .G2 0 0}}, {{index .G2 0 1}}],
+            [{{index .G2 1 0}}, {{index .G2 1 1}}]
+        );
+    }
+
+    function negate(G1Point memory p) internal pure returns (G1Point memory) {
+        uint q = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
+        if (p.X == 0 && p.Y == 0) {
+            return G1Point(0, 0);
+        }
+        return G1Point(p.X, q - (p.Y % q));
+    }
+
+    function addition(G1Point memory p1, G1Point memory p2) internal view returns (G1Point memory r) {
+        uint[4] memory input;
+        input[0] = p1.X;
+        input[1] = p1.Y;
+        input[2] = p2.X;
+        input[3] = p2.Y;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 6, input, 0x80, r, 0x40)
+        }
+        require(success, "pairing-add-failed");
+    }
+
+    function scalarMul(G1Point memory p, uint s) internal view returns (G1Point memory r) {
+        uint[3] memory input;
+        input[0] = p.X;
+        input[1] = p.Y;
+        input[2] = s;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 7, input, 0x60, r, 0x40)
+        }
+        require(success, "pairing-mul-failed");
+    }
+
+    function pairing(G1Point[] memory p1, G2Point[] memory p2) internal view returns (bool) {
+        require(p1.length == p2.length, "pairing-lengths-failed");
+        uint elements = p1.length;
+        uint inputSize = elements * 6;
+        uint[] memory input = new uint[](inputSize);
+        for (uint i = 0; i < elements; i++) {
+            input[i * 6 + 0] = p1[i].X;
+            input[i * 6 + 1] = p1[i].Y;
+            input[i * 6 + 2] = p2[i].X[0];
+            input[i * 6 + 3] = p2[i].X[1];
+            input[i * 6 + 4] = p2[i].Y[0];
+            input[i * 6 + 5] = p2[i].Y[1];
+        }
+        uint[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 8, add(input, 0x20), mul(inputSize, 0x20), out, 0x20)
+        }
+        require(success, "pairing-opcode-failed");
+        return out[0] != 0;
+    }
+
+    function pairingProd2(G1Point memory a1, G2Point memory a2, G1Point memory b1, G2Point memory b2) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](2);
+        G2Point[] memory p2 = new G2Point[](2);
+        p1[0] = a1;
+        p1[1] = b1;
+        p2[0] = a2;
+        p2[1] = b2;
+        return pairing(p1, p2);
+    }
+
+    function pairingProd3(G1Point memory a1, G2Point memory a2, G1Point memory b1, G2Point memory b2, G1Point memory c1, G2Point memory c2) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](3);
+        G2Point[] memory p2 = new G2Point[](3);
+        p1[0] = a1;
+        p1[1] = b1;
+        p1[2] = c1;
+        p2[0] = a2;
+        p2[1] = b2;
+        p2[2] = c2;
+        return pairing(p1, p2);
+    }
+}
+
+contract Verifier {
+    struct VerifyingKey {
+        Pairing.G2Point A;
+        Pairing.G1Point B;
+        Pairing.G2Point C;
+        Pairing.G2Point gamma;
+        Pairing.G1Point gammaBeta1;
+        Pairing.G2Point gammaBeta2;
+        Pairing.G2Point Z;
+        Pairing.G1Point[] IC;
+    }
+
+    struct Proof {
+        Pairing.G1Point A;
+        Pairing.G1Point A_p;
+        Pairing.G2Point B;
+        Pairing.G1Point B_p;
+        Pairing.G1Point C;
+        Pairing.G1Point C_p;
+        Pairing.G1Point K;
+        Pairing.G1Point H;
+    }
+
+    function verifyingKey() internal pure returns (VerifyingKey memory vk) {
+        vk.A = Pairing.G2Point([{{index .Vka 0 0}}, {{index .Vka 0 1}}], [{{index .Vka 1 0}}, {{index .Vka 1 1}}]);
+        vk.B = Pairing.G1Point({{index .Vkb 0}}, {{index .Vkb 1}});
+        vk.C = Pairing.G2Point([{{index .Vkc 0 0}}, {{index .Vkc 0 1}}], [{{index .Vkc 1 0}}, {{index .Vkc 1 1}}]);
+        vk.gamma = Pairing.G2Point([{{index .G2Kg 0 0}}, {{index .G2Kg 0 1}}], [{{index .G2Kg 1 0}}, {{index .G2Kg 1 1}}]);
+        vk.gammaBeta1 = Pairing.G1Point({{index .G1Kbg 0}}, {{index .G1Kbg 1}});
+        vk.gammaBeta2 = Pairing.G2Point([{{index .G2Kbg 0 0}}, {{index .G2Kbg 0 1}}], [{{index .G2Kbg 1 0}}, {{index .G2Kbg 1 1}}]);
+        vk.Z = Pairing.G2Point([{{index .Vkz 0 0}}, {{index .Vkz 0 1}}], [{{index .Vkz 1 0}}, {{index .Vkz 1 1}}]);
+        vk.IC = new Pairing.G1Point[]({{len .IC}});
+{{- range $i, $ic := .IC}}
+        vk.IC[{{$i}}] = Pairing.G1Point({{index $ic 0}}, {{index $ic 1}});
+{{- end}}
+    }
+
+    function verify(uint[] memory input, Proof memory proof) internal view returns (uint) {
+        uint snarkScalarField = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+        VerifyingKey memory vk = verifyingKey();
+        require(input.length + 1 == vk.IC.length, "verifier-bad-input");
+        // vk_x = IC[0] + sum(input[i] * IC[i+1])
+        Pairing.G1Point memory vk_x = vk.IC[0];
+        for (uint i = 0; i < input.length; i++) {
+            require(input[i] < snarkScalarField, "verifier-gte-snark-scalar-field");
+            vk_x = Pairing.addition(vk_x, Pairing.scalarMul(vk.IC[i + 1], input[i]));
+        }
+        // e(piA, Va) == e(piA', g2)
+        if (!Pairing.pairingProd2(proof.A, vk.A, Pairing.negate(proof.A_p), Pairing.P2())) {
+            return 1;
+        }
+        // e(Vb, piB) == e(piB', g2)
+        if (!Pairing.pairingProd2(vk.B, proof.B, Pairing.negate(proof.B_p), Pairing.P2())) {
+            return 2;
+        }
+        // e(piC, Vc) == e(piC', g2)
+        if (!Pairing.pairingProd2(proof.C, vk.C, Pairing.negate(proof.C_p), Pairing.P2())) {
+            return 3;
+        }
+        // e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB) == e(piK, g2Kgamma)
+        if (!Pairing.pairingProd3(
+            proof.K, vk.gamma,
+            Pairing.negate(Pairing.addition(vk_x, Pairing.addition(proof.A, proof.C))), vk.gammaBeta2,
+            Pairing.negate(vk.gammaBeta1), proof.B
+        )) {
+            return 4;
+        }
+        // e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2)
+        if (!Pairing.pairingProd3(
+            Pairing.addition(vk_x, proof.A), proof.B,
+            Pairing.negate(proof.H), vk.Z,
+            Pairing.negate(proof.C), Pairing.P2()
+        )) {
+            return 5;
+        }
+        return 0;
+    }
+
+    function verifyProof(
+        uint[2] memory a,
+        uint[2] memory a_p,
+        uint[2][2] memory b,
+        uint[2] memory b_p,
+        uint[2] memory c,
+        uint[2] memory c_p,
+        uint[2] memory h,
+        uint[2] memory k{{if .NPublic}},
+        uint[{{.NPublic}}] memory input{{end}}
+    ) public view returns (bool) {
+        Proof memory proof;
+        proof.A = Pairing.G1Point(a[0], a[1]);
+        proof.A_p = Pairing.G1Point(a_p[0], a_p[1]);
+        proof.B = Pairing.G2Point([b[0][0], b[0][1]], [b[1][0], b[1][1]]);
+        proof.B_p = Pairing.G1Point(b_p[0], b_p[1]);
+        proof.C = Pairing.G1Point(c[0], c[1]);
+        proof.C_p = Pairing.G1Point(c_p[0], c_p[1]);
+        proof.H = Pairing.G1Point(h[0], h[1]);
+        proof.K = Pairing.G1Point(k[0], k[1]);
+{{- if .NPublic}}
+        uint[] memory inputValues = new uint[](input.length);
+        for (uint i = 0; i < input.length; i++) {
+            inputValues[i] = input[i];
+        }
+{{- else}}
+        // the circuit has no public signals
+        uint[] memory inputValues = new uint[](0);
+{{- end}}
+        return verify(inputValues, proof) == 0;
+    }
+}
+`))
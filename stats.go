@@ -0,0 +1,26 @@
+package snark
+
+import (
+	"time"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// PGHR13 describes the costs of the proving system of this package, to
+// estimate the proving key size and prove time with circuit.Stats
+var PGHR13 = circuitcompiler.ProvingSystem{
+	Name: "PGHR13",
+	KeyPoints: func(s circuitcompiler.Stats) (int, int) {
+		// G1T, Pk.A, Pk.C, Pk.Kp, Pk.Ap, Pk.Bp, Pk.Cp in G1, and G2T, Pk.B in G2,
+		// one point of each for every signal
+		return 7 * s.Signals, 2 * s.Signals
+	},
+	ProverMuls: func(s circuitcompiler.Stats) (int, int) {
+		// PiA and PiAp over the private signals, PiBp, PiC, PiCp and PiKp over
+		// all the signals, PiH over the h(x) coefficients, and PiB in G2
+		private := s.Signals - 1 - s.PublicOutputs - s.PublicInputs
+		return 2*private + 4*s.Signals + s.Constraints, s.Signals
+	},
+	G1MulTime: 5 * time.Millisecond,
+	G2MulTime: 20 * time.Millisecond,
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: GPL-3.0
+// Verifier of the go-snark proofs, generated by go-snark
+pragma solidity >=0.6.0 <0.9.0;
+
+library Pairing {
+    struct G1Point {
+        uint X;
+        uint Y;
+    }
+    // the coordinates are X[0] * i + X[1]
+    struct G2Point {
+        uint[2] X;
+        uint[2] Y;
+    }
+
+    function P1() internal pure returns (G1Point memory) {
+        return G1Point(1, 2);
+    }
+
+    function P2() internal pure returns (G2Point memory) {
+        return G2Point(
+            [11559732032986387107991004021392285783925812861821192530917403151452391805634, 10857046999023057135944570762232829481370756359578518086990519993285655852781],
+            [4082367875863433681332203403145435568316851327593401208105741076214120093531, 8495653923123431417604973247489272438418190587263600148770280649306958101930]
+        );
+    }
+
+    function negate(G1Point memory p) internal pure returns (G1Point memory) {
+        uint q = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
+        if (p.X == 0 && p.Y == 0) {
+            return G1Point(0, 0);
+        }
+        return G1Point(p.X, q - (p.Y % q));
+    }
+
+    function addition(G1Point memory p1, G1Point memory p2) internal view returns (G1Point memory r) {
+        uint[4] memory input;
+        input[0] = p1.X;
+        input[1] = p1.Y;
+        input[2] = p2.X;
+        input[3] = p2.Y;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 6, input, 0x80, r, 0x40)
+        }
+        require(success, "pairing-add-failed");
+    }
+
+    function scalarMul(G1Point memory p, uint s) internal view returns (G1Point memory r) {
+        uint[3] memory input;
+        input[0] = p.X;
+        input[1] = p.Y;
+        input[2] = s;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 7, input, 0x60, r, 0x40)
+        }
+        require(success, "pairing-mul-failed");
+    }
+
+    function pairing(G1Point[] memory p1, G2Point[] memory p2) internal view returns (bool) {
+        require(p1.length == p2.length, "pairing-lengths-failed");
+        uint elements = p1.length;
+        uint inputSize = elements * 6;
+        uint[] memory input = new uint[](inputSize);
+        for (uint i = 0; i < elements; i++) {
+            input[i * 6 + 0] = p1[i].X;
+            input[i * 6 + 1] = p1[i].Y;
+            input[i * 6 + 2] = p2[i].X[0];
+            input[i * 6 + 3] = p2[i].X[1];
+            input[i * 6 + 4] = p2[i].Y[0];
+            input[i * 6 + 5] = p2[i].Y[1];
+        }
+        uint[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(sub(gas(), 2000), 8, add(input, 0x20), mul(inputSize, 0x20), out, 0x20)
+        }
+        require(success, "pairing-opcode-failed");
+        return out[0] != 0;
+    }
+
+    function pairingProd2(G1Point memory a1, G2Point memory a2, G1Point memory b1, G2Point memory b2) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](2);
+        G2Point[] memory p2 = new G2Point[](2);
+        p1[0] = a1;
+        p1[1] = b1;
+        p2[0] = a2;
+        p2[1] = b2;
+        return pairing(p1, p2);
+    }
+
+    function pairingProd3(G1Point memory a1, G2Point memory a2, G1Point memory b1, G2Point memory b2, G1Point memory c1, G2Point memory c2) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](3);
+        G2Point[] memory p2 = new G2Point[](3);
+        p1[0] = a1;
+        p1[1] = b1;
+        p1[2] = c1;
+        p2[0] = a2;
+        p2[1] = b2;
+        p2[2] = c2;
+        return pairing(p1, p2);
+    }
+}
+
+contract Verifier {
+    struct VerifyingKey {
+        Pairing.G2Point A;
+        Pairing.G1Point B;
+        Pairing.G2Point C;
+        Pairing.G2Point gamma;
+        Pairing.G1Point gammaBeta1;
+        Pairing.G2Point gammaBeta2;
+        Pairing.G2Point Z;
+        Pairing.G1Point[] IC;
+    }
+
+    struct Proof {
+        Pairing.G1Point A;
+        Pairing.G1Point A_p;
+        Pairing.G2Point B;
+        Pairing.G1Point B_p;
+        Pairing.G1Point C;
+        Pairing.G1Point C_p;
+        Pairing.G1Point K;
+        Pairing.G1Point H;
+    }
+
+    function verifyingKey() internal pure returns (VerifyingKey memory vk) {
+        vk.A = Pairing.G2Point([14583779054894525174450323658765874724019480979794335525732096752006891875705, 18029695676650738226693292988307914797657423701064905010927197838374790804409], [11474861747383700316476719153975578001603231366361248090558603872215261634898, 2140229616977736810657479771656733941598412651537078903776637920509952744750]);
+        vk.B = Pairing.G1Point(3353031288059533942658390886683067124040920775575537747144343083137631628272, 19321533766552368860946552437480515441416830039777911637913418824951667761761);
+        vk.C = Pairing.G2Point([18556147586753789634670778212244811446448229326945855846642767021074501673839, 18936818173480011669507163011118288089468827259971823710084038754632518263340], [13775476761357503446238925910346030822904460488609979964814810757616608848118, 18825831177813899069786213865729385895767511805925522466244528695074736584695]);
+        vk.gamma = Pairing.G2Point([12345624066896925082600651626583520268054356403303305150512393106955803260718, 10191129150170504690859455063377241352678147020731325090942140630855943625622], [13790151551682513054696583104432356791070435696840691503641536676885931241944, 16727484375212017249697795760885267597317766655549468217180521378213906474374]);
+        vk.gammaBeta1 = Pairing.G1Point(10744596414106452074759370245733544594153395043370666422502510773307029471145, 848677436511517736191562425154572367705380862894644942948681172815252343932);
+        vk.gammaBeta2 = Pairing.G2Point([4540444681147253467785307942530223364530218361853237193970751657229138047649, 20954117799226682825035885491234530437475518021362091509513177301640194298072], [11631839690097995216017572651900167465857396346217730511548857041925508482915, 21508930868448350162258892668132814424284302804699005394342512102884055673846]);
+        vk.Z = Pairing.G2Point([18551411094430470096460536606940536822990217226529861227533666875800903099477, 15512671280233143720612069991584289591749188907863576513414377951116606878472], [1711576522631428957817575436337311654689480489843856945284031697403898093784, 13376798835316611669264291046140500151806347092962367781523498857425536295743]);
+        vk.IC = new Pairing.G1Point[](1);
+        vk.IC[0] = Pairing.G1Point(3932705576657793550893430333273221375907985235130430286685735064194643946083, 18813763293032256545937756946359266117037834559191913266454084342712532869153);
+    }
+
+    function verify(uint[] memory input, Proof memory proof) internal view returns (uint) {
+        uint snarkScalarField = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+        VerifyingKey memory vk = verifyingKey();
+        require(input.length + 1 == vk.IC.length, "verifier-bad-input");
+        // vk_x = IC[0] + sum(input[i] * IC[i+1])
+        Pairing.G1Point memory vk_x = vk.IC[0];
+        for (uint i = 0; i < input.length; i++) {
+            require(input[i] < snarkScalarField, "verifier-gte-snark-scalar-field");
+            vk_x = Pairing.addition(vk_x, Pairing.scalarMul(vk.IC[i + 1], input[i]));
+        }
+        // e(piA, Va) == e(piA', g2)
+        if (!Pairing.pairingProd2(proof.A, vk.A, Pairing.negate(proof.A_p), Pairing.P2())) {
+            return 1;
+        }
+        // e(Vb, piB) == e(piB', g2)
+        if (!Pairing.pairingProd2(vk.B, proof.B, Pairing.negate(proof.B_p), Pairing.P2())) {
+            return 2;
+        }
+        // e(piC, Vc) == e(piC', g2)
+        if (!Pairing.pairingProd2(proof.C, vk.C, Pairing.negate(proof.C_p), Pairing.P2())) {
+            return 3;
+        }
+        // e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB) == e(piK, g2Kgamma)
+        if (!Pairing.pairingProd3(
+            proof.K, vk.gamma,
+            Pairing.negate(Pairing.addition(vk_x, Pairing.addition(proof.A, proof.C))), vk.gammaBeta2,
+            Pairing.negate(vk.gammaBeta1), proof.B
+        )) {
+            return 4;
+        }
+        // e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2)
+        if (!Pairing.pairingProd3(
+            Pairing.addition(vk_x, proof.A), proof.B,
+            Pairing.negate(proof.H), vk.Z,
+            Pairing.negate(proof.C), Pairing.P2()
+        )) {
+            return 5;
+        }
+        return 0;
+    }
+
+    function verifyProof(
+        uint[2] memory a,
+        uint[2] memory a_p,
+        uint[2][2] memory b,
+        uint[2] memory b_p,
+        uint[2] memory c,
+        uint[2] memory c_p,
+        uint[2] memory h,
+        uint[2] memory k
+    ) public view returns (bool) {
+        Proof memory proof;
+        proof.A = Pairing.G1Point(a[0], a[1]);
+        proof.A_p = Pairing.G1Point(a_p[0], a_p[1]);
+        proof.B = Pairing.G2Point([b[0][0], b[0][1]], [b[1][0], b[1][1]]);
+        proof.B_p = Pairing.G1Point(b_p[0], b_p[1]);
+        proof.C = Pairing.G1Point(c[0], c[1]);
+        proof.C_p = Pairing.G1Point(c_p[0], c_p[1]);
+        proof.H = Pairing.G1Point(h[0], h[1]);
+        proof.K = Pairing.G1Point(k[0], k[1]);
+        // the circuit has no public signals
+        uint[] memory inputValues = new uint[](0);
+        return verify(inputValues, proof) == 0;
+    }
+}